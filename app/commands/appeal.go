@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var AppealSelector = tb.ReplyMarkup{}
+var AppealButton = AppealSelector.Data("", "appeal")
+
+var appealDecisions = map[string]string{
+	"approve": "апелляция одобрена",
+	"deny":    "апелляция отклонена",
+}
+
+//Send appeal against ban or restriction in main chat to moderators on /appeal
+func Appeal(m *tb.Message) {
+	if !m.Private() {
+		_, err := utils.Bot.Reply(m, "Апелляцию можно подать только в личных сообщениях боту.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	chat, err := utils.ConfigChat(utils.Config.Telegram.Chat)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	member, err := utils.Bot.ChatMemberOf(chat, m.Sender)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	if member.Role != tb.Kicked && member.Role != tb.Restricted {
+		_, err := utils.Bot.Reply(m, "У тебя нет активных ограничений в чате.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var appeal utils.Appeal
+	result := utils.DB.Where(&utils.Appeal{UserID: m.Sender.ID}).Order("date DESC").Limit(1).Find(&appeal)
+	if result.RowsAffected != 0 && appeal.HandlerID == 0 {
+		_, err := utils.Bot.Reply(m, "Твоя апелляция уже на рассмотрении, дождись решения модераторов.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	cooldown := time.Duration(utils.Config.Appeals.Cooldown) * time.Second
+	if result.RowsAffected != 0 && appeal.Decision == "deny" && time.Since(appeal.HandledAt) < cooldown {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Твоя прошлая апелляция отклонена, новую можно подать через %v.", utils.FormatDuration(int64((cooldown-time.Since(appeal.HandledAt)).Seconds()))))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text string
+	if args := strings.SplitN(m.Text, " ", 2); len(args) == 2 {
+		text = strings.TrimSpace(args[1])
+	}
+	if text == "" {
+		_, err := utils.Bot.Reply(m, "Пример использования: <code>/appeal {почему ограничение нужно снять}</code>")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	appeal = utils.Appeal{
+		Date:   time.Now(),
+		UserID: m.Sender.ID,
+		ChatID: chat.ID,
+		Text:   text,
+	}
+	result = utils.DB.Create(&appeal)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	recipients, err := utils.StaffRecipients()
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	restriction := "бан"
+	if member.Role == tb.Restricted {
+		restriction = "ограничение"
+	}
+	if member.RestrictedUntil != 0 {
+		restriction += utils.RestrictionTimeMessage(member.RestrictedUntil)
+	} else {
+		restriction += " навсегда"
+	}
+	var tracked utils.Restriction
+	utils.DB.Where("chat_id = ? AND user_id = ?", chat.ID, m.Sender.ID).Order("date DESC").Limit(1).Find(&tracked)
+	staffText := fmt.Sprintf("Апелляция от %v (<code>%v</code>)\nОграничение: %v%v\n\n%v", utils.MentionUser(m.Sender), m.Sender.ID, restriction, utils.ReasonMessage(tracked.Reason), html.EscapeString(text))
+	markup := &tb.ReplyMarkup{}
+	id := strconv.Itoa(appeal.ID)
+	markup.Inline(markup.Row(
+		markup.Data("✅ Снять ограничение", AppealButton.Unique, id, "approve"),
+		markup.Data("❌ Отклонить", AppealButton.Unique, id, "deny"),
+	))
+	var sent int
+	for _, recipient := range recipients {
+		_, err = utils.Bot.Send(recipient, staffText, markup)
+		if err != nil {
+			continue
+		}
+		sent++
+	}
+	if sent == 0 {
+		utils.DB.Delete(&appeal)
+		_, err := utils.Bot.Reply(m, "Не удалось отправить апелляцию модераторам, попробуй позже.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	_, err = utils.Bot.Reply(m, "Апелляция отправлена модераторам, бот сообщит о решении.")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+//Execute moderator's decision on appeal button click
+func AppealAction(c *tb.Callback) {
+	data := strings.SplitN(c.Data, "|", 2)
+	if len(data) != 2 {
+		return
+	}
+	if _, ok := appealDecisions[data[1]]; !ok {
+		return
+	}
+	if !utils.IsAdminOrModer(c.Sender.Username) {
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: "Рассматривать апелляции могут только модераторы.", ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	var appeal utils.Appeal
+	result := utils.DB.First(&appeal, data[0])
+	if result.RowsAffected == 0 {
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: "Апелляция не найдена.", ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	result = utils.DB.Model(&utils.Appeal{}).Where("id = ? AND handler_id = 0", appeal.ID).Updates(map[string]interface{}{"handler_id": c.Sender.ID, "decision": data[1], "handled_at": time.Now()})
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, c.Message)
+		return
+	}
+	if result.RowsAffected != 1 {
+		utils.DB.First(&appeal, appeal.ID)
+		var handler tb.User
+		utils.DB.Where(&tb.User{ID: appeal.HandlerID}).Limit(1).Find(&handler)
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: fmt.Sprintf("Апелляция уже рассмотрена %v: %v.", utils.UserName(&handler), appealDecisions[appeal.Decision]), ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	chat, err := utils.Bot.ChatByID(strconv.FormatInt(appeal.ChatID, 10))
+	if err != nil {
+		appealRelease(appeal.ID)
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+	member, err := utils.Bot.ChatMemberOf(chat, &tb.User{ID: appeal.UserID})
+	if err != nil {
+		appealRelease(appeal.ID)
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+	var notice string
+	switch data[1] {
+	case "approve":
+		//Appeal has no message in chat, so moderation log has no context link
+		context := &tb.Message{Chat: chat}
+		switch member.Role {
+		case tb.Kicked:
+			err = utils.Bot.Unban(chat, member.User)
+			if err == nil {
+				err = utils.LogModeration(context, c.Sender, member.User, "unban", 0, "апелляция")
+			}
+		case tb.Restricted:
+			err = utils.RestoreRights(chat, member)
+			if err == nil {
+				err = utils.LogModeration(context, c.Sender, member.User, "unmute", 0, "апелляция")
+			}
+		}
+		notice = fmt.Sprintf("Твоя апелляция одобрена, ограничения в чате %v сняты.", html.EscapeString(chat.Title))
+	case "deny":
+		notice = fmt.Sprintf("Твоя апелляция отклонена. Подать новую можно через %v.", utils.FormatDuration(int64(utils.Config.Appeals.Cooldown)))
+	}
+	if err != nil {
+		appealRelease(appeal.ID)
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: fmt.Sprintf("Ошибка: %v", err.Error()), ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	err = utils.Bot.Respond(c, &tb.CallbackResponse{Text: appealDecisions[data[1]]})
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+	}
+	_, err = utils.Bot.EditReplyMarkup(c.Message, nil)
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+	}
+	_, err = utils.Bot.Reply(c.Message, fmt.Sprintf("%v: %v.", utils.MentionUser(c.Sender), appealDecisions[data[1]]))
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+	}
+	//Appeal came from private chat, so bot can write to user
+	_, err = utils.Bot.Send(member.User, notice)
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+}
+
+//Release appeal claim, so other moderator can handle it
+func appealRelease(id int) {
+	utils.DB.Model(&utils.Appeal{}).Where("id = ?", id).Updates(map[string]interface{}{"handler_id": 0, "decision": "", "handled_at": time.Time{}})
+}
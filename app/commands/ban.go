@@ -21,16 +21,28 @@ func Ban(m *tb.Message) {
 		return
 	}
 	var text = strings.Split(m.Text, " ")
-	if (m.ReplyTo == nil && len(text) < 2) || (m.ReplyTo != nil && len(text) > 2) {
-		_, err := utils.Bot.Reply(m, "Пример использования: <code>/ban {ID или никнейм}</code>\nИли отправь в ответ на какое-либо сообщение <code>/ban</code>\nЕсли нужно забанить на время, то добавь время в секундах через пробел.")
+	usage := "Пример использования: <code>/ban {ID или никнейм} [время] [причина]</code>\nИли отправь в ответ на какое-либо сообщение <code>/ban [время] [причина]</code>\nЕсли нужно забанить на время, то добавь время через пробел, например <code>30m</code>, <code>2h</code>, <code>1d12h</code>, <code>1w</code> или <code>forever</code>, от 30 секунд до 366 дней.\nПосле времени можно указать причину."
+	if m.ReplyTo == nil && len(text) < 2 {
+		_, err := utils.Bot.Reply(m, usage)
 		if err != nil {
 			utils.ErrorReporting(err, m)
 			return
 		}
 		return
 	}
-	target, untildate, err := utils.FindUserInMessage(*m)
+	target, untildate, reason, err := utils.FindUserInMessage(*m)
 	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
+		if err == utils.ErrInvalidDuration {
+			_, err := utils.Bot.Reply(m, usage)
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			return
+		}
 		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя или время бана:\n<code>%v</code>", err.Error()))
 		if err != nil {
 			utils.ErrorReporting(err, m)
@@ -57,7 +69,11 @@ func Ban(m *tb.Message) {
 		}
 		return
 	}
-	_, err = utils.Bot.Reply(m, fmt.Sprintf("Пользователь <a href=\"tg://user?id=%v\">%v</a> забанен%v.", target.ID, utils.UserFullName(&target), utils.RestrictionTimeMessage(untildate)))
+	err = utils.LogModeration(m, m.Sender, &target, "ban", untildate, reason)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	_, err = utils.Bot.Reply(m, fmt.Sprintf("Пользователь <a href=\"tg://user?id=%v\">%v</a> забанен%v.%v", target.ID, utils.UserFullName(&target), utils.RestrictionTimeMessage(untildate), utils.ReasonMessage(reason)), utils.RememberUndo(utils.UndoAction{Chat: m.Chat, Target: &target, Action: "ban"}))
 	if err != nil {
 		utils.ErrorReporting(err, m)
 		return
@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"html"
+	"strconv"
+	"strings"
+)
+
+var blacklistActions = map[string]string{
+	"delete": "удаление",
+	"warn":   "удаление и предупреждение",
+	"mute":   "мьют",
+	"ban":    "бан",
+}
+
+//Manage blacklist of words and regexes on /blacklist
+func Blacklist(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	switch {
+	case len(text) > 3 && text[1] == "add":
+		blacklistAdd(m, text)
+	case len(text) == 2 && text[1] == "list":
+		blacklistList(m)
+	case len(text) == 3 && text[1] == "del":
+		blacklistDel(m, text[2])
+	default:
+		_, err := utils.Bot.Reply(m, "Пример использования:\n<code>/blacklist add {delete, warn, mute или ban} [время] {слово или /регулярка/}</code>\n<code>/blacklist list</code>\n<code>/blacklist del {ID}</code>\nСлова ищутся целиком, без учёта регистра, похожих латинских букв и повторов символов. Время указывается только для mute и ban, например <code>2h</code>.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+	}
+}
+
+func blacklistAdd(m *tb.Message, text []string) {
+	var entry utils.Blacklist
+	entry.ChatID = m.Chat.ID
+	entry.Action = strings.ToLower(text[2])
+	if _, ok := blacklistActions[entry.Action]; !ok {
+		_, err := utils.Bot.Reply(m, "Действие должно быть одним из: <code>delete</code>, <code>warn</code>, <code>mute</code>, <code>ban</code>.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	pattern := text[3:]
+	if (entry.Action == "mute" || entry.Action == "ban") && len(pattern) > 1 {
+		if _, err := utils.ParseDuration(pattern[0]); err == nil {
+			entry.Duration = pattern[0]
+			pattern = pattern[1:]
+		}
+	}
+	entry.Pattern = strings.Join(pattern, " ")
+	if len(entry.Pattern) > 2 && strings.HasPrefix(entry.Pattern, "/") && strings.HasSuffix(entry.Pattern, "/") {
+		entry.Pattern = entry.Pattern[1 : len(entry.Pattern)-1]
+		entry.Regex = true
+		_, err := utils.BlacklistRegexp(entry.Pattern)
+		if err != nil {
+			_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось разобрать регулярку:\n<code>%v</code>", html.EscapeString(err.Error())))
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			return
+		}
+	}
+	result := utils.DB.Create(&entry)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось сохранить запись чёрного списка:\n<code>%v</code>.", result.Error))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	_, err := utils.Bot.Reply(m, fmt.Sprintf("Запись #%v добавлена в чёрный список: %v.", entry.ID, blacklistEntry(entry)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+func blacklistEntry(entry utils.Blacklist) string {
+	pattern := fmt.Sprintf("<code>%v</code>", html.EscapeString(entry.Pattern))
+	if entry.Regex {
+		pattern = fmt.Sprintf("<code>/%v/</code>", html.EscapeString(entry.Pattern))
+	}
+	action := blacklistActions[entry.Action]
+	if entry.Duration != "" {
+		action += " на " + entry.Duration
+	}
+	return fmt.Sprintf("%v → %v", pattern, action)
+}
+
+func blacklistList(m *tb.Message) {
+	var entries []utils.Blacklist
+	result := utils.DB.Where(&utils.Blacklist{ChatID: m.Chat.ID}).Find(&entries)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	if len(entries) == 0 {
+		_, err := utils.Bot.Reply(m, "Чёрный список этого чата пуст.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var list string
+	for _, entry := range entries {
+		list += fmt.Sprintf("#%v %v, срабатываний: %v\n", entry.ID, blacklistEntry(entry), entry.Hits)
+		if len(list) > 3900 {
+			_, err := utils.Bot.Reply(m, list)
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			list = ""
+		}
+	}
+	if list == "" {
+		return
+	}
+	_, err := utils.Bot.Reply(m, list)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+func blacklistDel(m *tb.Message, id string) {
+	entryID, err := strconv.Atoi(strings.TrimPrefix(id, "#"))
+	if err != nil {
+		_, err := utils.Bot.Reply(m, "ID записи должен быть числом.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	result := utils.DB.Where(&utils.Blacklist{ChatID: m.Chat.ID}).Delete(&utils.Blacklist{ID: entryID})
+	if result.RowsAffected != 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Запись #%v удалена из чёрного списка.", entryID))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+	} else {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Запись #%v не найдена.", entryID))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+	}
+}
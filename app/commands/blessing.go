@@ -51,6 +51,10 @@ func Blessing(m *tb.Message) {
 		utils.ErrorReporting(err, m)
 		return
 	}
+	err = utils.TrackRestriction(m.Chat, m.Sender, utils.Bot.Me, "mute", ChatMember.RestrictedUntil, "самоубийство")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
 	_, err = utils.Bot.Send(m.Chat, fmt.Sprintf("<code>💥 %v выбрал лёгкий путь.\nРеспавн через %v0 минут.</code>", utils.UserFullName(m.Sender), duelist.Deaths))
 	if err != nil {
 		utils.ErrorReporting(err, m)
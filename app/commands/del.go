@@ -30,6 +30,10 @@ func Del(m *tb.Message) {
 		return
 	}
 	result := utils.DB.Delete(&utils.Get{Name: strings.ToLower(text[1])})
+	if result.RowsAffected != 0 {
+		utils.DB.Where(&utils.Trigger{GetName: strings.ToLower(text[1])}).Delete(&utils.Trigger{})
+		utils.DB.Where(&utils.GetUsage{GetName: strings.ToLower(text[1])}).Delete(&utils.GetUsage{})
+	}
 	if result.RowsAffected != 0 {
 		_, err := utils.Bot.Reply(m, fmt.Sprintf("Гет <code>%v</code> удалён.", text[1]))
 		if err != nil {
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"html"
+	"strings"
+	"time"
+)
+
+//Ban user in all federation chats on /fban
+func Fban(m *tb.Message) {
+	federationAction(m, "ban")
+}
+
+//Unban user in all federation chats on /funban
+func Funban(m *tb.Message) {
+	federationAction(m, "unban")
+}
+
+//Mute user in all federation chats on /fmute
+func Fmute(m *tb.Message) {
+	federationAction(m, "mute")
+}
+
+func federationAction(m *tb.Message, action string) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	usage := fmt.Sprintf("Пример использования: <code>/f%v {ID или никнейм} [причина]</code>\nИли отправь в ответ на какое-либо сообщение <code>/f%v [причина]</code>", action, action)
+	if action != "unban" {
+		usage = fmt.Sprintf("Пример использования: <code>/f%v {ID или никнейм} [время] [причина]</code>\nИли отправь в ответ на какое-либо сообщение <code>/f%v [время] [причина]</code>\nВремя указывается, например, как <code>30m</code>, <code>2h</code>, <code>1d12h</code>, <code>1w</code> или <code>forever</code>.", action, action)
+	}
+	if m.ReplyTo == nil && len(text) < 2 {
+		_, err := utils.Bot.Reply(m, usage)
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	target, untildate, reason, err := utils.FindUserInMessage(*m)
+	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
+		if err == utils.ErrInvalidDuration {
+			_, err := utils.Bot.Reply(m, usage)
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			return
+		}
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	switch action {
+	case "ban":
+		ban := utils.FederationBan{UserID: target.ID, ActorID: m.Sender.ID, Reason: reason, Date: time.Now()}
+		if untildate-30 > time.Now().Unix() {
+			ban.UntilDate = untildate
+		}
+		result := utils.DB.Save(&ban)
+		if result.Error != nil {
+			utils.ErrorReporting(result.Error, m)
+			return
+		}
+	case "unban":
+		untildate = 0
+		result := utils.DB.Delete(&utils.FederationBan{UserID: target.ID})
+		if result.Error != nil {
+			utils.ErrorReporting(result.Error, m)
+			return
+		}
+	}
+	done, failed, err := utils.FederationApply(&target, m.Sender, action, untildate, reason)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	var message string
+	switch action {
+	case "ban":
+		message = fmt.Sprintf("Пользователь %v забанен в федерации%v", utils.MentionUser(&target), utils.RestrictionTimeMessage(untildate))
+	case "unban":
+		message = fmt.Sprintf("Пользователь %v разбанен в федерации", utils.MentionUser(&target))
+	case "mute":
+		message = fmt.Sprintf("Пользователь %v больше не может отправлять сообщения в чатах федерации%v", utils.MentionUser(&target), utils.RestrictionTimeMessage(untildate))
+	}
+	message += fmt.Sprintf(", чатов: %v.%v", done, utils.ReasonMessage(reason))
+	if len(failed) != 0 {
+		message += fmt.Sprintf("\nНе удалось:\n%v", html.EscapeString(strings.Join(failed, "\n")))
+	}
+	err = utils.LogModeration(m, m.Sender, &target, "f"+action, untildate, reason)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	_, err = utils.Bot.Reply(m, message)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
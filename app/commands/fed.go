@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"html"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+type fedManifest struct {
+	Version int                   `json:"version"`
+	Created time.Time             `json:"created"`
+	Bans    []utils.FederationBan `json:"bans"`
+}
+
+//Manage federation of chats on /fed
+func Fed(m *tb.Message) {
+	if !utils.IsAdmin(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	var command string
+	if len(text) == 2 {
+		command = text[1]
+	}
+	if m.Private() && command != "list" && command != "export" && command != "import" {
+		command = ""
+	}
+	var message string
+	switch command {
+	case "add":
+		result := utils.DB.Save(&utils.FederationChat{ChatID: m.Chat.ID, Title: m.Chat.Title})
+		if result.Error != nil {
+			utils.ErrorReporting(result.Error, m)
+			return
+		}
+		message = "Чат добавлен в федерацию."
+	case "del":
+		result := utils.DB.Delete(&utils.FederationChat{ChatID: m.Chat.ID})
+		if result.Error != nil {
+			utils.ErrorReporting(result.Error, m)
+			return
+		}
+		message = "Чат удалён из федерации."
+	case "optout", "optin":
+		result := utils.DB.Model(&utils.FederationChat{ChatID: m.Chat.ID}).Update("opt_out", command == "optout")
+		if result.Error != nil {
+			utils.ErrorReporting(result.Error, m)
+			return
+		}
+		switch {
+		case result.RowsAffected == 0:
+			message = "Чат не состоит в федерации."
+		case command == "optout":
+			message = "Баны и мьюты федерации больше не применяются в этом чате."
+		default:
+			message = "Баны и мьюты федерации снова применяются в этом чате."
+		}
+	case "list":
+		fedList(m)
+		return
+	case "export":
+		fedExport(m)
+		return
+	case "import":
+		fedImport(m)
+		return
+	default:
+		message = "Пример использования:\n<code>/fed add</code> - добавить текущий чат в федерацию\n<code>/fed del</code> - удалить текущий чат из федерации\n<code>/fed optout</code> и <code>/fed optin</code> - отключить или включить баны федерации в текущем чате\n<code>/fed list</code> - список чатов федерации\n<code>/fed export</code> - выгрузить список банов федерации в JSON\nОтправь в ответ на JSON из <code>/fed export</code> <code>/fed import</code>, чтобы загрузить список банов.\nВ список банов федерации попадают только баны через <code>/fban</code>, обычный <code>/ban</code> действует только в текущем чате."
+	}
+	_, err := utils.Bot.Reply(m, message)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+func fedList(m *tb.Message) {
+	var chats []utils.FederationChat
+	result := utils.DB.Find(&chats)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	var bans int64
+	utils.DB.Model(&utils.FederationBan{}).Count(&bans)
+	list := fmt.Sprintf("Чаты федерации, банов в списке: %v.\n", bans)
+	for _, chat := range chats {
+		list += fmt.Sprintf("\n%v (<code>%v</code>)", html.EscapeString(chat.Title), chat.ChatID)
+		if chat.OptOut {
+			list += " - отключен"
+		}
+	}
+	if len(chats) == 0 {
+		list += "\nЧатов пока нет, добавь чат с помощью <code>/fed add</code>."
+	}
+	_, err := utils.Bot.Reply(m, list)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+func fedExport(m *tb.Message) {
+	manifest := fedManifest{Version: 1, Created: time.Now()}
+	result := utils.DB.Order("date").Find(&manifest.Bans)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	data, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	_, err = utils.Bot.Send(m.Sender, &tb.Document{
+		File:     tb.FromReader(bytes.NewReader(data)),
+		FileName: fmt.Sprintf("fedbans-%v.json", time.Now().Format("2006-01-02")),
+		Caption:  fmt.Sprintf("Банов в федерации: %v.", len(manifest.Bans)),
+	})
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	if m.Private() {
+		return
+	}
+	_, err = utils.Bot.Reply(m, "Список отправлен в личку.\nЕсли список не пришел, то убедитесь, что бот запущен и не заблокирован в личке.")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+func fedImport(m *tb.Message) {
+	if m.ReplyTo == nil || m.ReplyTo.Document == nil {
+		_, err := utils.Bot.Reply(m, "Отправь в ответ на JSON из <code>/fed export</code> <code>/fed import</code>")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	reader, _, err := utils.OpenFile(m.ReplyTo.Document.FileID)
+	if err != nil {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось скачать файл:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	data, err := ioutil.ReadAll(reader)
+	_ = reader.Close()
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	var manifest fedManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось прочитать JSON:\n<code>%v</code>", html.EscapeString(err.Error())))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var imported int
+	for _, ban := range manifest.Bans {
+		if ban.UserID == 0 {
+			continue
+		}
+		result := utils.DB.Save(&ban)
+		if result.Error != nil {
+			utils.ErrorReporting(result.Error, m)
+			continue
+		}
+		imported++
+	}
+	_, err = utils.Bot.Reply(m, fmt.Sprintf("Импортировано банов: %v из %v.\nПользователи будут забанены при входе в чаты федерации.", imported, len(manifest.Bans)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/NexonSU/telegram-go-chatbot/app/utils"
 	tb "gopkg.in/tucnak/telebot.v2"
+	"html"
 	"strings"
 )
 
@@ -22,77 +23,48 @@ func Get(m *tb.Message) {
 		}
 		return
 	}
-	result := utils.DB.Where(&utils.Get{Name: strings.ToLower(text[1])}).First(&get)
+	result := utils.DB.Scopes(utils.NotExpired).Where(&utils.Get{Name: strings.ToLower(text[1])}).First(&get)
 	if result.RowsAffected != 0 {
-		switch {
-		case get.Type == "Animation":
-			_, err := utils.Bot.Reply(m, &tb.Animation{
-				File:    tb.File{FileID: get.Data},
-				Caption: get.Caption,
-			})
-			if err != nil {
-				utils.ErrorReporting(err, m)
-				return
-			}
-		case get.Type == "Audio":
-			_, err := utils.Bot.Reply(m, &tb.Audio{
-				File:    tb.File{FileID: get.Data},
-				Caption: get.Caption,
-			})
-			if err != nil {
-				utils.ErrorReporting(err, m)
-				return
-			}
-		case get.Type == "Photo":
-			_, err := utils.Bot.Reply(m, &tb.Photo{
-				File:    tb.File{FileID: get.Data},
-				Caption: get.Caption,
-			})
-			if err != nil {
-				utils.ErrorReporting(err, m)
-				return
-			}
-		case get.Type == "Video":
-			_, err := utils.Bot.Reply(m, &tb.Video{
-				File:    tb.File{FileID: get.Data},
-				Caption: get.Caption,
-			})
-			if err != nil {
-				utils.ErrorReporting(err, m)
-				return
-			}
-		case get.Type == "Voice":
-			_, err := utils.Bot.Reply(m, &tb.Voice{
-				File: tb.File{FileID: get.Data},
-			})
-			if err != nil {
-				utils.ErrorReporting(err, m)
-				return
-			}
-		case get.Type == "Document":
-			_, err := utils.Bot.Reply(m, &tb.Document{
-				File:    tb.File{FileID: get.Data},
-				Caption: get.Caption,
-			})
+		err := utils.SendGet(m.Chat, get, &tb.SendOptions{ReplyTo: m})
+		if err == utils.ErrUnknownGetType {
+			_, err := utils.Bot.Reply(m, fmt.Sprintf("Ошибка при определении типа гета, я не знаю тип <code>%v</code>.", get.Type))
 			if err != nil {
 				utils.ErrorReporting(err, m)
 				return
 			}
-		case get.Type == "Text":
-			_, err := utils.Bot.Reply(m, get.Data)
+		} else if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		err = utils.CountGetUsage(get.Name, m.Sender.ID)
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+	} else {
+		similar, err := utils.SimilarGets(text[1], 5)
+		if err != nil {
+			utils.ErrorReporting(err, m)
+		}
+		if len(similar) == 0 {
+			_, err := utils.Bot.Reply(m, fmt.Sprintf("Гет <code>%v</code> не найден.", html.EscapeString(text[1])))
 			if err != nil {
 				utils.ErrorReporting(err, m)
 				return
 			}
-		default:
-			_, err := utils.Bot.Reply(m, fmt.Sprintf("Ошибка при определении типа гета, я не знаю тип <code>%v</code>.", get.Type))
-			if err != nil {
-				utils.ErrorReporting(err, m)
-				return
+			return
+		}
+		markup := &tb.ReplyMarkup{}
+		var rows []tb.Row
+		for _, name := range similar {
+			if len(GetallGetButton.Unique)+len(name) > 60 {
+				rows = append(rows, markup.Row(markup.QueryChat(name, name)))
+			} else {
+				rows = append(rows, markup.Row(markup.Data(name, GetallGetButton.Unique, name)))
 			}
 		}
-	} else {
-		_, err := utils.Bot.Reply(m, fmt.Sprintf("Гет <code>%v</code> не найден.", text[1]))
+		markup.Inline(rows...)
+		_, err = utils.Bot.Reply(m, fmt.Sprintf("Гет <code>%v</code> не найден. Возможно, ты имел в виду:", html.EscapeString(text[1])), markup)
 		if err != nil {
 			utils.ErrorReporting(err, m)
 			return
@@ -1,32 +1,224 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"github.com/NexonSU/telegram-go-chatbot/app/utils"
 	tb "gopkg.in/tucnak/telebot.v2"
+	"html"
+	"strconv"
 	"strings"
 )
 
+const getallPageSize = 20
+const getallNoCategory = "Без категории"
+
+var GetallSelector = tb.ReplyMarkup{}
+var GetallCategoriesButton = GetallSelector.Data("« Категории", "getall_cats")
+var GetallCategoryButton = GetallSelector.Data("", "getall_cat")
+var GetallGetButton = GetallSelector.Data("", "getall_get")
+
 //Send list of Gets to user on /getall
 func Getall(m *tb.Message) {
 	if m.Chat.Username != utils.Config.Telegram.Chat && !utils.IsAdminOrModer(m.Sender.Username) {
 		return
 	}
-	var getall []string
-	var get utils.Get
-	result, _ := utils.DB.Model(&utils.Get{}).Rows()
-	for result.Next() {
-		err := utils.DB.ScanRows(result, &get)
+	var text = strings.SplitN(m.Text, " ", 2)
+	var message string
+	var markup *tb.ReplyMarkup
+	var err error
+	if len(text) == 2 {
+		message, markup, err = getallCategoryPage(strings.ToLower(strings.TrimSpace(text[1])), 0)
+	} else {
+		message, markup, err = getallCategoriesPage(0)
+	}
+	if err != nil {
+		_, err := utils.Bot.Reply(m, err.Error())
 		if err != nil {
 			utils.ErrorReporting(err, m)
 			return
 		}
-		getall = append(getall, get.Name)
+		return
 	}
-	_, err := utils.Bot.Reply(m, fmt.Sprintf("Доступные геты: %v", strings.Join(getall[:], ", ")))
+	_, err = utils.Bot.Reply(m, message, markup)
 	if err != nil {
 		utils.ErrorReporting(err, m)
 		return
 	}
-	return
+}
+
+//Show categories page on button click
+func GetallCategories(c *tb.Callback) {
+	page, _ := strconv.Atoi(c.Data)
+	message, markup, err := getallCategoriesPage(page)
+	if err != nil {
+		err = utils.Bot.Respond(c, &tb.CallbackResponse{Text: err.Error(), ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	getallEdit(c, message, markup)
+}
+
+//Show category page on button click
+func GetallCategory(c *tb.Callback) {
+	data := strings.SplitN(c.Data, "|", 2)
+	if len(data) != 2 {
+		return
+	}
+	page, _ := strconv.Atoi(data[0])
+	message, markup, err := getallCategoryPage(data[1], page)
+	if err != nil {
+		err = utils.Bot.Respond(c, &tb.CallbackResponse{Text: err.Error(), ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	getallEdit(c, message, markup)
+}
+
+//Send Get on button click
+func GetallGet(c *tb.Callback) {
+	var get utils.Get
+	result := utils.DB.Scopes(utils.NotExpired).Where(&utils.Get{Name: c.Data}).First(&get)
+	if result.RowsAffected == 0 {
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: fmt.Sprintf("Гет %v не найден.", c.Data), ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	err := utils.Bot.Respond(c, &tb.CallbackResponse{})
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+	err = utils.SendGet(c.Message.Chat, get)
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+	err = utils.CountGetUsage(get.Name, c.Sender.ID)
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+}
+
+func getallEdit(c *tb.Callback, message string, markup *tb.ReplyMarkup) {
+	_, err := utils.Bot.Edit(c.Message, message, markup)
+	if err != nil && err != tb.ErrMessageNotModified {
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+	err = utils.Bot.Respond(c, &tb.CallbackResponse{})
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+}
+
+func getallCategoriesPage(page int) (string, *tb.ReplyMarkup, error) {
+	var category string
+	var count int64
+	markup := &tb.ReplyMarkup{}
+	var buttons []tb.Btn
+	result, err := utils.DB.Model(&utils.Get{}).Scopes(utils.NotExpired).Select("category, COUNT(*) as count").Group("category").Order("category").Rows()
+	if err != nil {
+		return "", nil, err
+	}
+	defer result.Close()
+	for result.Next() {
+		err := result.Scan(&category, &count)
+		if err != nil {
+			return "", nil, err
+		}
+		name := category
+		if name == "" {
+			name = getallNoCategory
+		}
+		buttons = append(buttons, markup.Data(fmt.Sprintf("%v (%v)", name, count), GetallCategoryButton.Unique, "0", category))
+	}
+	if len(buttons) == 0 {
+		return "", nil, errors.New("Гетов пока нет.")
+	}
+	page, rows := getallPaginate(markup, buttons, page, func(page int) tb.Btn {
+		return markup.Data("", GetallCategoriesButton.Unique, strconv.Itoa(page))
+	})
+	markup.Inline(rows...)
+	return fmt.Sprintf("Категории гетов, страница %v из %v:", page+1, getallPages(len(buttons))), markup, nil
+}
+
+func getallCategoryPage(category string, page int) (string, *tb.ReplyMarkup, error) {
+	var names []string
+	if strings.EqualFold(category, getallNoCategory) {
+		category = ""
+	}
+	result := utils.DB.Model(&utils.Get{}).Scopes(utils.NotExpired).Where("category = ?", category).Order("name").Pluck("name", &names)
+	if result.Error != nil {
+		return "", nil, result.Error
+	}
+	if len(names) == 0 {
+		return "", nil, fmt.Errorf("Категория %v не найдена.", html.EscapeString(category))
+	}
+	markup := &tb.ReplyMarkup{}
+	var buttons []tb.Btn
+	for _, name := range names {
+		if len(GetallGetButton.Unique)+len(name) > 60 {
+			buttons = append(buttons, markup.QueryChat(name, name))
+		} else {
+			buttons = append(buttons, markup.Data(name, GetallGetButton.Unique, name))
+		}
+	}
+	page, rows := getallPaginate(markup, buttons, page, func(page int) tb.Btn {
+		return markup.Data("", GetallCategoryButton.Unique, strconv.Itoa(page), category)
+	})
+	rows = append(rows, markup.Row(markup.Data(GetallCategoriesButton.Text, GetallCategoriesButton.Unique, "0")))
+	markup.Inline(rows...)
+	if category == "" {
+		category = getallNoCategory
+	}
+	return fmt.Sprintf("Геты в категории %v, страница %v из %v:", html.EscapeString(category), page+1, getallPages(len(buttons))), markup, nil
+}
+
+func getallPages(count int) int {
+	return (count + getallPageSize - 1) / getallPageSize
+}
+
+//Split buttons on pages, two buttons in a row, with navigation row at the bottom
+func getallPaginate(markup *tb.ReplyMarkup, buttons []tb.Btn, page int, pageButton func(page int) tb.Btn) (int, []tb.Row) {
+	var rows []tb.Row
+	pages := getallPages(len(buttons))
+	if page < 0 || page >= pages {
+		page = 0
+	}
+	end := (page + 1) * getallPageSize
+	if end > len(buttons) {
+		end = len(buttons)
+	}
+	pageButtons := buttons[page*getallPageSize : end]
+	for i := 0; i < len(pageButtons); i += 2 {
+		if i+1 < len(pageButtons) {
+			rows = append(rows, markup.Row(pageButtons[i], pageButtons[i+1]))
+		} else {
+			rows = append(rows, markup.Row(pageButtons[i]))
+		}
+	}
+	if pages > 1 {
+		var navigation []tb.Btn
+		if page > 0 {
+			prev := pageButton(page - 1)
+			prev.Text = "« Назад"
+			navigation = append(navigation, prev)
+		}
+		if page < pages-1 {
+			next := pageButton(page + 1)
+			next.Text = "Вперёд »"
+			navigation = append(navigation, next)
+		}
+		rows = append(rows, markup.Row(navigation...))
+	}
+	return page, rows
 }
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"net/url"
+	"strings"
+)
+
+//Set Get URL buttons on /getbuttons
+func Getbuttons(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var get utils.Get
+	var lines = strings.Split(m.Text, "\n")
+	var text = strings.Split(lines[0], " ")
+	if len(text) != 2 {
+		_, err := utils.Bot.Reply(m, "Пример использования:\n<code>/getbuttons {гет}\nМагазин https://example.com/shop | Patreon https://patreon.com/example\nСайт https://example.com</code>\nКаждая строка — ряд кнопок, кнопки в ряду разделяются <code>|</code>.\nЧтобы убрать кнопки, отправь только первую строку.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	get.Name = strings.ToLower(text[1])
+	result := utils.DB.Where(&utils.Get{Name: get.Name}).First(&get)
+	if result.RowsAffected == 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Гет <code>%v</code> не найден.", get.Name))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	if get.Type == "Album" && len(lines) > 1 {
+		_, err := utils.Bot.Reply(m, "К альбому нельзя прикрепить кнопки.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var rows [][]utils.GetButton
+	for _, line := range lines[1:] {
+		var row []utils.GetButton
+		for _, button := range strings.Split(line, "|") {
+			button = strings.TrimSpace(button)
+			if button == "" {
+				continue
+			}
+			separator := strings.LastIndex(button, " ")
+			if separator == -1 {
+				_, err := utils.Bot.Reply(m, fmt.Sprintf("Не указан текст или ссылка кнопки <code>%v</code>.", button))
+				if err != nil {
+					utils.ErrorReporting(err, m)
+					return
+				}
+				return
+			}
+			link, err := url.Parse(button[separator+1:])
+			if err != nil || (link.Scheme != "https" && link.Scheme != "http" && link.Scheme != "tg") {
+				_, err := utils.Bot.Reply(m, fmt.Sprintf("Неверная ссылка в кнопке <code>%v</code>.", button))
+				if err != nil {
+					utils.ErrorReporting(err, m)
+					return
+				}
+				return
+			}
+			row = append(row, utils.GetButton{Text: strings.TrimSpace(button[:separator]), URL: link.String()})
+		}
+		if len(row) != 0 {
+			rows = append(rows, row)
+		}
+	}
+	get.Buttons = ""
+	if len(rows) != 0 {
+		data, err := json.Marshal(rows)
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		get.Buttons = string(data)
+	}
+	result = utils.DB.Model(&get).Update("buttons", get.Buttons)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	if len(rows) == 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Кнопки гета <code>%v</code> удалены.", get.Name))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	_, err := utils.Bot.Reply(m, fmt.Sprintf("Кнопки гета <code>%v</code> сохранены.", get.Name), &tb.ReplyMarkup{InlineKeyboard: utils.GetKeyboard(get)})
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
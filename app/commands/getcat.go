@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strings"
+)
+
+//Set Get category on /getcat
+func Getcat(m *tb.Message) {
+	if m.Chat.Username != utils.Config.Telegram.Chat && !utils.IsAdminOrModer(m.Sender.Username) {
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	if len(text) < 2 {
+		_, err := utils.Bot.Reply(m, "Пример использования: <code>/getcat {гет} {категория}</code>\nЧтобы убрать гет из категории, не указывай её.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	name := strings.ToLower(text[1])
+	category := strings.ToLower(strings.Join(text[2:], " "))
+	if len(category) > 32 || strings.ContainsAny(category, "|<>&") || category == strings.ToLower(getallNoCategory) {
+		_, err := utils.Bot.Reply(m, "Название категории должно быть не длиннее 32 байт и не содержать символов <code>|&lt;&gt;&amp;</code>.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	result := utils.DB.Model(&utils.Get{}).Where(&utils.Get{Name: name}).Update("category", category)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	if result.RowsAffected == 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Гет <code>%v</code> не найден.", name))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	if category == "" {
+		category = getallNoCategory
+	}
+	_, err := utils.Bot.Reply(m, fmt.Sprintf("Гет <code>%v</code> перенесён в категорию <code>%v</code>.", name, category))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
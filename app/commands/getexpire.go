@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strings"
+	"time"
+)
+
+//Set Get expiry date on /getexpire
+func Getexpire(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var get utils.Get
+	var text = strings.Split(m.Text, " ")
+	if len(text) < 3 || len(text) > 4 {
+		_, err := utils.Bot.Reply(m, "Пример использования: <code>/getexpire {гет} {ДД.ММ.ГГГГ} [ЧЧ:ММ]</code>\nЧтобы гет не истекал, укажи <code>never</code> вместо даты.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	get.Name = strings.ToLower(text[1])
+	result := utils.DB.Where(&utils.Get{Name: get.Name}).First(&get)
+	if result.RowsAffected == 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Гет <code>%v</code> не найден.", get.Name))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	if text[2] == "never" {
+		result = utils.DB.Model(&get).Update("expires_at", nil)
+		if result.Error != nil {
+			utils.ErrorReporting(result.Error, m)
+			return
+		}
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Гет <code>%v</code> больше не истекает.", get.Name))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	date := strings.Join(text[2:], " ")
+	if len(text) == 3 {
+		date += " 00:00"
+	}
+	expiresAt, err := time.ParseInLocation("02.01.2006 15:04", date, time.Local)
+	if err != nil || expiresAt.Before(time.Now()) {
+		_, err := utils.Bot.Reply(m, "Укажи дату в будущем в формате <code>ДД.ММ.ГГГГ</code> или <code>ДД.ММ.ГГГГ ЧЧ:ММ</code>.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	result = utils.DB.Model(&get).Update("expires_at", expiresAt)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	_, err = utils.Bot.Reply(m, fmt.Sprintf("Гет <code>%v</code> будет удалён %v.", get.Name, expiresAt.Format("02.01.2006 15:04")))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
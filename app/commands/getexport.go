@@ -0,0 +1,273 @@
+package commands
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type getManifest struct {
+	Version int                `json:"version"`
+	Created time.Time          `json:"created"`
+	Gets    []getManifestEntry `json:"gets"`
+}
+
+type getManifestEntry struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Data     string            `json:"data,omitempty"`
+	Caption  string            `json:"caption,omitempty"`
+	Category string            `json:"category,omitempty"`
+	Buttons  string            `json:"buttons,omitempty"`
+	Expires  *time.Time        `json:"expires,omitempty"`
+	Files    []getManifestFile `json:"files,omitempty"`
+}
+
+type getManifestFile struct {
+	Type    string `json:"type"`
+	Path    string `json:"path"`
+	Caption string `json:"caption,omitempty"`
+}
+
+//Send zip with all Gets and their media on /getexport
+func Getexport(m *tb.Message) {
+	if !utils.IsAdmin(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var gets []utils.Get
+	result := utils.DB.Order("name").Find(&gets)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	_, err := utils.Bot.Reply(m, fmt.Sprintf("Экспортирую %v гетов, это может занять время.", len(gets)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	var failed []string
+	var exported int
+	var parts int
+	var part *getexportPart
+	for i, get := range gets {
+		entry := getManifestEntry{
+			Name:     get.Name,
+			Type:     get.Type,
+			Caption:  get.Caption,
+			Category: get.Category,
+			Buttons:  get.Buttons,
+			Expires:  get.ExpiresAt,
+		}
+		//Files are downloaded before writing, so failed album doesn't leave files without manifest entry
+		var files []getexportData
+		switch get.Type {
+		case "Text", "Poll":
+			entry.Data = get.Data
+		case "Album":
+			var items []utils.AlbumItem
+			err := json.Unmarshal([]byte(get.Data), &items)
+			if err != nil {
+				failed = append(failed, get.Name)
+				continue
+			}
+			for j, item := range items {
+				file, err := getexportDownload(item.FileID, fmt.Sprintf("files/%v/%v", i, j))
+				if err != nil {
+					break
+				}
+				files = append(files, file)
+				entry.Files = append(entry.Files, getManifestFile{Type: item.Type, Path: file.path, Caption: item.Caption})
+			}
+			if len(files) != len(items) {
+				failed = append(failed, get.Name)
+				continue
+			}
+		default:
+			file, err := getexportDownload(get.Data, fmt.Sprintf("files/%v/0", i))
+			if err != nil {
+				failed = append(failed, get.Name)
+				continue
+			}
+			files = append(files, file)
+			entry.Files = append(entry.Files, getManifestFile{Type: get.Type, Path: file.path})
+		}
+		//Manifest entry is written at the end, but counts too
+		size := int64(len(entry.Data) + len(entry.Caption) + len(entry.Buttons))
+		for _, file := range files {
+			size += int64(len(file.data))
+		}
+		if size > getexportPartSize {
+			failed = append(failed, fmt.Sprintf("%v (больше %v МБ)", get.Name, getexportPartSize>>20))
+			continue
+		}
+		if part != nil {
+			written, err := part.size()
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				part.remove()
+				return
+			}
+			if written+size > getexportPartSize {
+				parts++
+				err = part.send(m.Sender, parts)
+				if err != nil {
+					utils.ErrorReporting(err, m)
+					return
+				}
+				exported += len(part.manifest.Gets)
+				part = nil
+			}
+		}
+		if part == nil {
+			part, err = newGetexportPart()
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+		}
+		for _, file := range files {
+			err = part.add(file)
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				part.remove()
+				return
+			}
+		}
+		part.manifest.Gets = append(part.manifest.Gets, entry)
+	}
+	if part != nil {
+		parts++
+		err = part.send(m.Sender, parts)
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		exported += len(part.manifest.Gets)
+	}
+	report := fmt.Sprintf("Экспортировано гетов: %v, архивов: %v.", exported, parts)
+	if len(failed) != 0 {
+		report += fmt.Sprintf("\nНе удалось экспортировать: %v", strings.Join(failed, ", "))
+	}
+	if runes := []rune(report); len(runes) > 4000 {
+		report = string(runes[:4000]) + "…"
+	}
+	_, err = utils.Bot.Send(m.Sender, report)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	_, err = utils.Bot.Reply(m, "Архив отправлен в личку.\nЕсли архив не пришел, то убедитесь, что бот запущен и не заблокирован в личке.")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+//Archive size limit, bot can't download files over 20 MB back for /getimport
+const getexportPartSize = 19 << 20
+
+//Export archive, that is written to temporary file on disk
+type getexportPart struct {
+	file     *os.File
+	archive  *zip.Writer
+	manifest getManifest
+}
+
+func newGetexportPart() (*getexportPart, error) {
+	file, err := ioutil.TempFile("", "gets-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	return &getexportPart{
+		file:     file,
+		archive:  zip.NewWriter(file),
+		manifest: getManifest{Version: 1, Created: time.Now()},
+	}, nil
+}
+
+//Bytes written to archive so far
+func (part *getexportPart) size() (int64, error) {
+	err := part.archive.Flush()
+	if err != nil {
+		return 0, err
+	}
+	return part.file.Seek(0, io.SeekCurrent)
+}
+
+func (part *getexportPart) add(file getexportData) error {
+	writer, err := part.archive.Create(file.path)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(file.data)
+	return err
+}
+
+func (part *getexportPart) remove() {
+	_ = part.file.Close()
+	_ = os.Remove(part.file.Name())
+}
+
+//Finish archive with its own manifest and send it, every archive can be imported separately
+func (part *getexportPart) send(to *tb.User, number int) error {
+	defer part.remove()
+	manifestFile, err := part.archive.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	err = json.NewEncoder(manifestFile).Encode(part.manifest)
+	if err != nil {
+		return err
+	}
+	err = part.archive.Close()
+	if err != nil {
+		return err
+	}
+	_, err = utils.Bot.Send(to, &tb.Document{
+		File:     tb.FromDisk(part.file.Name()),
+		FileName: fmt.Sprintf("gets-%v-%v.zip", time.Now().Format("2006-01-02"), number),
+		Caption:  fmt.Sprintf("Архив %v, гетов: %v.", number, len(part.manifest.Gets)),
+	})
+	return err
+}
+
+//Downloaded file and its path in archive
+type getexportData struct {
+	path string
+	data []byte
+}
+
+//Download file by FileID
+func getexportDownload(fileID string, name string) (getexportData, error) {
+	reader, filePath, err := utils.OpenFile(fileID)
+	if err != nil {
+		return getexportData{}, err
+	}
+	defer func(reader io.ReadCloser) {
+		err := reader.Close()
+		if err != nil {
+			return
+		}
+	}(reader)
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return getexportData{}, err
+	}
+	return getexportData{path: name + filepath.Ext(filePath), data: data}, nil
+}
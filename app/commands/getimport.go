@@ -0,0 +1,238 @@
+package commands
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"gorm.io/gorm/clause"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+//Restore Gets from /getexport zip on /getimport
+func Getimport(m *tb.Message) {
+	if !utils.IsAdmin(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	if m.ReplyTo == nil || m.ReplyTo.Document == nil || len(text) > 2 || (len(text) == 2 && text[1] != "overwrite") {
+		_, err := utils.Bot.Reply(m, "Отправь в ответ на архив из /getexport <code>/getimport</code>\nЧтобы заменить существующие геты, добавь <code>overwrite</code>.\nФайлы загружаются через личку, поэтому бот должен быть запущен в личке.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	overwrite := len(text) == 2
+	reader, _, err := utils.OpenFile(m.ReplyTo.Document.FileID)
+	if err != nil {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось скачать архив:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	data, err := ioutil.ReadAll(reader)
+	_ = reader.Close()
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось открыть архив:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	files := make(map[string]*zip.File)
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	var manifest getManifest
+	err = getimportManifest(files["manifest.json"], &manifest)
+	if err != nil {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось прочитать manifest.json:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	_, err = utils.Bot.Reply(m, fmt.Sprintf("Импортирую %v гетов, это может занять время.", len(manifest.Gets)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	var imported int
+	var conflicts []string
+	var failed []string
+	for _, entry := range manifest.Gets {
+		get := utils.Get{
+			Name:      strings.ToLower(entry.Name),
+			Type:      entry.Type,
+			Data:      entry.Data,
+			Caption:   entry.Caption,
+			Category:  entry.Category,
+			Buttons:   entry.Buttons,
+			ExpiresAt: entry.Expires,
+		}
+		var existing utils.Get
+		result := utils.DB.Where(&utils.Get{Name: get.Name}).First(&existing)
+		if result.RowsAffected != 0 && !overwrite {
+			conflicts = append(conflicts, get.Name)
+			continue
+		}
+		switch entry.Type {
+		case "Text", "Poll":
+		case "Album":
+			var items []utils.AlbumItem
+			for _, file := range entry.Files {
+				fileID, err := getimportUpload(m.Sender, file.Type, files[file.Path])
+				if err != nil {
+					break
+				}
+				items = append(items, utils.AlbumItem{Type: file.Type, FileID: fileID, Caption: file.Caption})
+			}
+			if len(items) == 0 || len(items) != len(entry.Files) {
+				failed = append(failed, get.Name)
+				continue
+			}
+			data, _ := json.Marshal(items)
+			get.Data = string(data)
+		default:
+			if len(entry.Files) != 1 {
+				failed = append(failed, get.Name)
+				continue
+			}
+			get.Data, err = getimportUpload(m.Sender, entry.Type, files[entry.Files[0].Path])
+			if err != nil {
+				failed = append(failed, get.Name)
+				continue
+			}
+		}
+		result = utils.DB.Clauses(clause.OnConflict{
+			UpdateAll: true,
+		}).Create(get)
+		if result.Error != nil {
+			failed = append(failed, get.Name)
+			continue
+		}
+		imported++
+	}
+	report := fmt.Sprintf("Импортировано гетов: %v.", imported)
+	if len(conflicts) != 0 {
+		report += fmt.Sprintf("\nУже существуют, пропущены: %v", strings.Join(conflicts, ", "))
+	}
+	if len(failed) != 0 {
+		report += fmt.Sprintf("\nНе удалось импортировать: %v", strings.Join(failed, ", "))
+	}
+	if runes := []rune(report); len(runes) > 4000 {
+		report = string(runes[:4000]) + "…"
+	}
+	_, err = utils.Bot.Reply(m, report)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+func getimportManifest(file *zip.File, manifest *getManifest) error {
+	if file == nil {
+		return errors.New("файл не найден")
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer func(reader io.ReadCloser) {
+		err := reader.Close()
+		if err != nil {
+			return
+		}
+	}(reader)
+	return json.NewDecoder(reader).Decode(manifest)
+}
+
+//Upload file from archive to private chat with admin and return its new FileID
+func getimportUpload(to *tb.User, fileType string, file *zip.File) (string, error) {
+	if file == nil {
+		return "", errors.New("файл не найден в архиве")
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer func(reader io.ReadCloser) {
+		err := reader.Close()
+		if err != nil {
+			return
+		}
+	}(reader)
+	media := tb.FromReader(reader)
+	fileName := filepath.Base(file.Name)
+	var what interface{}
+	switch fileType {
+	case "Animation":
+		what = &tb.Animation{File: media, FileName: fileName}
+	case "Audio":
+		what = &tb.Audio{File: media, FileName: fileName}
+	case "Photo":
+		what = &tb.Photo{File: media}
+	case "Video":
+		what = &tb.Video{File: media, FileName: fileName}
+	case "Voice":
+		what = &tb.Voice{File: media}
+	case "Document":
+		what = &tb.Document{File: media, FileName: fileName}
+	case "Sticker":
+		what = &tb.Sticker{File: media}
+	case "VideoNote":
+		what = &tb.VideoNote{File: media}
+	default:
+		return "", utils.ErrUnknownGetType
+	}
+	message, err := utils.Bot.Send(to, what, tb.Silent)
+	if err != nil {
+		return "", err
+	}
+	defer func(message *tb.Message) {
+		_ = utils.Bot.Delete(message)
+	}(message)
+	switch {
+	case fileType == "Animation" && message.Animation != nil:
+		return message.Animation.FileID, nil
+	case fileType == "Audio" && message.Audio != nil:
+		return message.Audio.FileID, nil
+	case fileType == "Photo" && message.Photo != nil:
+		return message.Photo.FileID, nil
+	case fileType == "Video" && message.Video != nil:
+		return message.Video.FileID, nil
+	case fileType == "Voice" && message.Voice != nil:
+		return message.Voice.FileID, nil
+	case fileType == "Document" && message.Document != nil:
+		return message.Document.FileID, nil
+	case fileType == "Sticker" && message.Sticker != nil:
+		return message.Sticker.FileID, nil
+	case fileType == "VideoNote" && message.VideoNote != nil:
+		return message.VideoNote.FileID, nil
+	}
+	return "", fmt.Errorf("Telegram не вернул файл типа %v", fileType)
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Send Gets unused for N months on /getstale
+func Getstale(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	var months = 3
+	if len(text) == 2 {
+		argMonths, err := strconv.Atoi(text[1])
+		if err != nil || argMonths < 1 {
+			_, err := utils.Bot.Reply(m, "Пример использования: <code>/getstale {количество месяцев}</code>")
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			return
+		}
+		months = argMonths
+	}
+	var getstale []string
+	result := utils.DB.Model(&utils.Get{}).Where("name NOT IN (?)", utils.DB.Model(&utils.GetUsage{}).Select("get_name").Where("date >= ?", time.Now().AddDate(0, -months, 0))).Order("name").Pluck("name", &getstale)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	if len(getstale) == 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Все геты использовались за последние %v мес.", months))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var list = fmt.Sprintf("Геты, не использовавшиеся %v мес.: ", months)
+	for i, name := range getstale {
+		if len(list) > 3900 {
+			_, err := utils.Bot.Reply(m, list)
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			list = ""
+		} else if i != 0 {
+			list += ", "
+		}
+		list += name
+	}
+	_, err := utils.Bot.Reply(m, list)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strconv"
+	"strings"
+)
+
+//Send most used Gets on /gettop
+func Gettop(m *tb.Message) {
+	if m.Chat.Username != utils.Config.Telegram.Chat && !utils.IsAdminOrModer(m.Sender.Username) {
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	var limit = 10
+	var i = 0
+	var name string
+	var count int64
+	if len(text) == 2 {
+		argLimit, err := strconv.Atoi(text[1])
+		if err != nil || argLimit < 1 || argLimit > 50 {
+			_, err := utils.Bot.Reply(m, "Укажите количество гетов от 1 до 50.")
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			return
+		}
+		limit = argLimit
+	}
+	var gettop = fmt.Sprintf("Топ-%v гетов:\n\n", limit)
+	result, err := utils.DB.Select("get_name, SUM(count) as uses").Table("get_usages").Group("get_name").Order("uses DESC").Limit(limit).Rows()
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	defer result.Close()
+	for result.Next() {
+		err := result.Scan(&name, &count)
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		i++
+		gettop += fmt.Sprintf("%v. <code>%v</code> - %v раз(а)\n", i, name, count)
+	}
+	if i == 0 {
+		gettop = "Геты ещё ни разу не использовались."
+	}
+	_, err = utils.Bot.Reply(m, gettop)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
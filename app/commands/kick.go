@@ -22,16 +22,19 @@ func Kick(m *tb.Message) {
 		return
 	}
 	var text = strings.Split(m.Text, " ")
-	if (m.ReplyTo == nil && len(text) == 1) || (m.ReplyTo != nil && len(text) != 2) {
-		_, err := utils.Bot.Reply(m, "Пример использования: <code>/kick {ID или никнейм}</code>\nИли отправь в ответ на какое-либо сообщение <code>/kick</code>")
+	if m.ReplyTo == nil && len(text) < 2 {
+		_, err := utils.Bot.Reply(m, "Пример использования: <code>/kick {ID или никнейм} [причина]</code>\nИли отправь в ответ на какое-либо сообщение <code>/kick [причина]</code>")
 		if err != nil {
 			utils.ErrorReporting(err, m)
 			return
 		}
 		return
 	}
-	target, _, err := utils.FindUserInMessage(*m)
+	target, args, err := utils.FindUserWithArgs(*m, 0)
 	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
 		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
 		if err != nil {
 			utils.ErrorReporting(err, m)
@@ -39,6 +42,7 @@ func Kick(m *tb.Message) {
 		}
 		return
 	}
+	reason := strings.Join(args, " ")
 	TargetChatMember, err := utils.Bot.ChatMemberOf(m.Chat, &target)
 	if err != nil {
 		_, err := utils.Bot.Reply(m, fmt.Sprintf("Ошибка определения пользователя чата:\n<code>%v</code>", err.Error()))
@@ -67,7 +71,11 @@ func Kick(m *tb.Message) {
 		}
 		return
 	}
-	_, err = utils.Bot.Reply(m, fmt.Sprintf("Пользователь <a href=\"tg://user?id=%v\">%v</a> исключен.", target.ID, utils.UserFullName(&target)))
+	err = utils.LogModeration(m, m.Sender, &target, "kick", 0, reason)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	_, err = utils.Bot.Reply(m, fmt.Sprintf("Пользователь <a href=\"tg://user?id=%v\">%v</a> исключен.%v", target.ID, utils.UserFullName(&target), utils.ReasonMessage(reason)), utils.RememberUndo(utils.UndoAction{Chat: m.Chat, Target: &target, Action: "kick"}))
 	if err != nil {
 		utils.ErrorReporting(err, m)
 		return
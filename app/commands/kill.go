@@ -23,7 +23,7 @@ func Kill(m *tb.Message) {
 		return
 	}
 	var text = strings.Split(m.Text, " ")
-	if (m.ReplyTo == nil && len(text) != 2) || (m.ReplyTo != nil && len(text) != 1) {
+	if (m.ReplyTo == nil && len(text) < 2) || (m.ReplyTo != nil && len(text) != 1) {
 		_, err := utils.Bot.Reply(m, "Пример использования: <code>/kill {ID или никнейм}</code>\nИли отправь в ответ на какое-либо сообщение <code>/kill</code>")
 		if err != nil {
 			utils.ErrorReporting(err, m)
@@ -31,8 +31,11 @@ func Kill(m *tb.Message) {
 		}
 		return
 	}
-	target, _, err := utils.FindUserInMessage(*m)
+	target, _, _, err := utils.FindUserInMessage(*m)
 	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
 		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
 		if err != nil {
 			utils.ErrorReporting(err, m)
@@ -64,13 +67,21 @@ func Kill(m *tb.Message) {
 		utils.ErrorReporting(result.Error, m)
 		return
 	}
+	err = utils.SaveRights(m.Chat, ChatMember)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
 	ChatMember.RestrictedUntil = time.Now().Add(time.Second * time.Duration(600*duelist.Deaths)).Unix()
 	err = utils.Bot.Restrict(m.Chat, ChatMember)
 	if err != nil {
 		utils.ErrorReporting(err, m)
 		return
 	}
-	_, err = utils.Bot.Send(m.Chat, fmt.Sprintf("💥 %v пристрелил %v.\n%v отправился на респавн на %v0 минут.", utils.UserFullName(m.Sender), utils.UserFullName(&target), utils.UserFullName(&target), duelist.Deaths))
+	err = utils.LogModeration(m, m.Sender, &target, "kill", ChatMember.RestrictedUntil, "")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	_, err = utils.Bot.Send(m.Chat, fmt.Sprintf("💥 %v пристрелил %v.\n%v отправился на респавн на %v0 минут.", utils.UserFullName(m.Sender), utils.UserFullName(&target), utils.UserFullName(&target), duelist.Deaths), utils.RememberUndo(utils.UndoAction{Chat: m.Chat, Target: &target, Action: "kill"}))
 	if err != nil {
 		utils.ErrorReporting(err, m)
 		return
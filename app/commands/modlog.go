@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"html"
+	"strings"
+)
+
+//Send user's moderation history on /modlog
+func Modlog(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	if (m.ReplyTo == nil && len(text) < 2) || (m.ReplyTo != nil && len(text) != 1) {
+		_, err := utils.Bot.Reply(m, "Пример использования: <code>/modlog {ID или никнейм}</code>\nИли отправь в ответ на какое-либо сообщение <code>/modlog</code>")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	target, _, _, err := utils.FindUserInMessage(*m)
+	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var actions []utils.ModerationAction
+	result := utils.DB.Where(&utils.ModerationAction{TargetID: target.ID}).Order("date DESC").Limit(30).Find(&actions)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	if len(actions) == 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("У %v нет записей в журнале модерации.", utils.UserFullName(&target)))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var actorIDs []int
+	for _, action := range actions {
+		actorIDs = append(actorIDs, action.ActorID)
+	}
+	var users []tb.User
+	utils.DB.Where("id IN ?", actorIDs).Find(&users)
+	actors := make(map[int]string)
+	for _, user := range users {
+		actors[user.ID] = utils.UserName(&user)
+	}
+	actors[utils.Bot.Me.ID] = "бот"
+	var modlog = fmt.Sprintf("Журнал модерации %v:\n\n", utils.MentionUser(&target))
+	for _, action := range actions {
+		name, ok := utils.ModerationActionNames[action.Action]
+		if !ok {
+			name = action.Action
+		}
+		actor, ok := actors[action.ActorID]
+		if !ok {
+			actor = fmt.Sprint(action.ActorID)
+		}
+		modlog += fmt.Sprintf("%v %v", action.Date.Format("02.01.2006 15:04"), name)
+		if action.Duration != 0 {
+			modlog += fmt.Sprintf(" на %v", utils.FormatDuration(action.Duration))
+		}
+		modlog += fmt.Sprintf(" (%v)", html.EscapeString(actor))
+		if action.Reason != "" {
+			modlog += fmt.Sprintf(": %v", html.EscapeString(action.Reason))
+		}
+		modlog += "\n"
+	}
+	_, err = utils.Bot.Reply(m, modlog)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
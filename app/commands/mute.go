@@ -21,16 +21,36 @@ func Mute(m *tb.Message) {
 		return
 	}
 	var text = strings.Split(m.Text, " ")
-	if (m.ReplyTo == nil && len(text) < 2) || (m.ReplyTo != nil && len(text) > 2) {
-		_, err := utils.Bot.Reply(m, "Пример использования: <code>/mute {ID или никнейм}</code>\nИли отправь в ответ на какое-либо сообщение <code>/mute</code>\nЕсли нужно замьютить на время, то добавь время в секундах через пробел.")
+	var mode string
+	var skip int
+	if len(text) > 1 {
+		if _, ok := utils.MuteModes[text[1]]; ok {
+			mode = text[1]
+			skip = 1
+		}
+	}
+	usage := "Пример использования: <code>/mute {ID или никнейм} [время] [причина]</code>\nИли отправь в ответ на какое-либо сообщение <code>/mute [время] [причина]</code>\nЕсли нужно замьютить на время, то добавь время через пробел, например <code>30m</code>, <code>2h</code>, <code>1d12h</code>, <code>1w</code> или <code>forever</code>, от 30 секунд до 366 дней.\nПосле времени можно указать причину.\nЧтобы запретить только медиа, стикеры и GIF, ссылки или опросы, но оставить возможность писать текст, используй <code>/mute media</code>, <code>/mute stickers</code>, <code>/mute links</code> или <code>/mute polls</code>."
+	if m.ReplyTo == nil && len(text) < 2+skip {
+		_, err := utils.Bot.Reply(m, usage)
 		if err != nil {
 			utils.ErrorReporting(err, m)
 			return
 		}
 		return
 	}
-	target, untildate, err := utils.FindUserInMessage(*m)
+	target, untildate, reason, err := utils.FindUserAfterArgs(*m, skip)
 	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
+		if err == utils.ErrInvalidDuration {
+			_, err := utils.Bot.Reply(m, usage)
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			return
+		}
 		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя или время ограничения:\n<code>%v</code>", err.Error()))
 		if err != nil {
 			utils.ErrorReporting(err, m)
@@ -47,7 +67,26 @@ func Mute(m *tb.Message) {
 		}
 		return
 	}
-	TargetChatMember.CanSendMessages = false
+	if mode != "" && TargetChatMember.Role == tb.Restricted && !TargetChatMember.CanSendMessages {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("%v уже не может отправлять сообщения, частичный мьют не нужен.", utils.UserFullName(&target)))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	err = utils.SaveRights(m.Chat, TargetChatMember)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	if mode != "" {
+		TargetChatMember.Rights, err = utils.SendRights(m.Chat, TargetChatMember)
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+	}
+	utils.ApplyMuteMode(&TargetChatMember.Rights, mode)
 	TargetChatMember.RestrictedUntil = untildate
 	err = utils.Bot.Restrict(m.Chat, TargetChatMember)
 	if err != nil {
@@ -58,7 +97,15 @@ func Mute(m *tb.Message) {
 		}
 		return
 	}
-	_, err = utils.Bot.Reply(m, fmt.Sprintf("Пользователь <a href=\"tg://user?id=%v\">%v</a> больше не может отправлять сообщения%v.", target.ID, utils.UserFullName(&target), utils.RestrictionTimeMessage(untildate)))
+	forbidden := "сообщения"
+	if mode != "" {
+		forbidden = utils.MuteModes[mode]
+	}
+	err = utils.LogModeration(m, m.Sender, &target, "mute"+mode, untildate, reason)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	_, err = utils.Bot.Reply(m, fmt.Sprintf("Пользователь <a href=\"tg://user?id=%v\">%v</a> больше не может отправлять %v%v.%v", target.ID, utils.UserFullName(&target), forbidden, utils.RestrictionTimeMessage(untildate), utils.ReasonMessage(reason)), utils.RememberUndo(utils.UndoAction{Chat: m.Chat, Target: &target, Action: "mute"}))
 	if err != nil {
 		utils.ErrorReporting(err, m)
 		return
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strings"
+	"time"
+)
+
+//Save staff note about user on /note
+func Note(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	if !utils.IsStaffChat(m.Chat) {
+		_, err := utils.Bot.Reply(m, "Заметки доступны только в личке бота или в чате модераторов.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	if (m.ReplyTo == nil && len(text) < 3) || (m.ReplyTo != nil && len(text) < 2) {
+		_, err := utils.Bot.Reply(m, "Пример использования: <code>/note {ID или никнейм} {текст}</code>\nИли отправь в ответ на какое-либо сообщение <code>/note {текст}</code>")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	target, args, err := utils.FindUserWithArgs(*m, 0)
+	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	if len(args) == 0 {
+		_, err := utils.Bot.Reply(m, "Текст заметки не может быть пустым.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	note := utils.Note{
+		UserID:   target.ID,
+		AuthorID: m.Sender.ID,
+		Text:     strings.Join(args, " "),
+		Date:     time.Now(),
+	}
+	result := utils.DB.Create(&note)
+	if result.Error != nil {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось сохранить заметку:\n<code>%v</code>", result.Error))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	_, err = utils.Bot.Reply(m, fmt.Sprintf("Заметка #%v про %v сохранена.", note.ID, utils.UserFullName(&target)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+//Send staff notes about user on /notes
+func Notes(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	if !utils.IsStaffChat(m.Chat) {
+		_, err := utils.Bot.Reply(m, "Заметки доступны только в личке бота или в чате модераторов.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	if (m.ReplyTo == nil && len(text) < 2) || (m.ReplyTo != nil && len(text) != 1) {
+		_, err := utils.Bot.Reply(m, "Пример использования: <code>/notes {ID или никнейм}</code>\nИли отправь в ответ на какое-либо сообщение <code>/notes</code>")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	target, _, err := utils.FindUserWithArgs(*m, 0)
+	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	notes, err := utils.UserNotes(target.ID, 0)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	if len(notes) == 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Про %v нет заметок.", utils.UserFullName(&target)))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	list := fmt.Sprintf("Заметки про %v:\n\n", utils.MentionUser(&target))
+	for _, line := range strings.SplitAfter(utils.NotesMessage(notes), "\n") {
+		if len(list)+len(line) > 3900 {
+			_, err := utils.Bot.Reply(m, list)
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			list = ""
+		}
+		list += line
+	}
+	if strings.TrimSpace(list) == "" {
+		return
+	}
+	_, err = utils.Bot.Reply(m, list)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
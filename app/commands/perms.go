@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strings"
+	"time"
+)
+
+var memberRoles = map[tb.MemberStatus]string{
+	tb.Creator:       "создатель",
+	tb.Administrator: "администратор",
+	tb.Member:        "участник",
+	tb.Restricted:    "ограничен",
+	tb.Left:          "не в чате",
+	tb.Kicked:        "забанен",
+}
+
+//Send what chat member can do on /perms
+func Perms(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	if (m.ReplyTo == nil && len(text) < 2) || (m.ReplyTo != nil && len(text) != 1) {
+		_, err := utils.Bot.Reply(m, "Пример использования: <code>/perms {ID или никнейм}</code>\nИли отправь в ответ на какое-либо сообщение <code>/perms</code>")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	target, _, _, err := utils.FindUserInMessage(*m)
+	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	member, err := utils.Bot.ChatMemberOf(m.Chat, &target)
+	if err != nil {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Ошибка определения пользователя чата:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	perms := fmt.Sprintf("Права %v в чате:\nСтатус: %v", utils.MentionUser(&target), memberRoles[member.Role])
+	if member.Role == tb.Restricted {
+		if member.RestrictedUntil == 0 {
+			perms += ", навсегда"
+		} else {
+			perms += fmt.Sprintf(", до %v", time.Unix(member.RestrictedUntil, 0).Format("02.01.2006 15:04"))
+		}
+	}
+	if member.Role != tb.Left && member.Role != tb.Kicked {
+		rights, err := utils.SendRights(m.Chat, member)
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		perms += "\n"
+		for _, right := range []struct {
+			allowed bool
+			name    string
+		}{
+			{rights.CanSendMessages, "сообщения"},
+			{rights.CanSendMedia, "медиа"},
+			{rights.CanSendOther, "стикеры и GIF"},
+			{rights.CanSendPolls, "опросы"},
+			{rights.CanAddPreviews, "превью ссылок"},
+		} {
+			mark := "❌"
+			if right.allowed {
+				mark = "✅"
+			}
+			perms += fmt.Sprintf("\n%v %v", mark, right.name)
+		}
+	}
+	var restrictions []utils.Restriction
+	utils.DB.Where(&utils.Restriction{ChatID: m.Chat.ID, UserID: target.ID}).Find(&restrictions)
+	if len(restrictions) != 0 {
+		perms += "\n\nОграничения бота:"
+		for _, restriction := range restrictions {
+			name, ok := utils.MuteModes[restriction.Type]
+			if ok {
+				name = fmt.Sprintf("запрет: %v", name)
+			} else {
+				name = utils.ModerationActionNames[restriction.Type]
+			}
+			perms += fmt.Sprintf("\n%v", name)
+			if restriction.UntilDate == 0 {
+				perms += " навсегда"
+			} else {
+				perms += fmt.Sprintf(" до %v", time.Unix(restriction.UntilDate, 0).Format("02.01.2006 15:04"))
+			}
+		}
+	}
+	_, err = utils.Bot.Reply(m, perms)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
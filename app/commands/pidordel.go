@@ -21,8 +21,11 @@ func Pidordel(m *tb.Message) {
 	}
 	var user tb.User
 	var pidor utils.PidorList
-	user, _, err := utils.FindUserInMessage(*m)
+	user, _, _, err := utils.FindUserInMessage(*m)
 	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
 		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
 		if err != nil {
 			utils.ErrorReporting(err, m)
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const purgeLimit = 500
+
+//Delete messages on /purge
+func Purge(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	var ids []int
+	var target tb.User
+	var err error
+	switch {
+	case m.ReplyTo != nil && len(text) == 1:
+		if m.ID-m.ReplyTo.ID > purgeLimit {
+			_, err := utils.Bot.Reply(m, fmt.Sprintf("За раз можно удалить не больше %v сообщений.", purgeLimit))
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			return
+		}
+		for id := m.ID - 1; id >= m.ReplyTo.ID; id-- {
+			ids = append(ids, id)
+		}
+	case m.ReplyTo == nil && len(text) >= 2:
+		args := text[1:]
+		if len(text) != 2 {
+			target, args, err = utils.FindUserWithArgs(*m, 0)
+			if err != nil {
+				if err == utils.ErrUserChoice {
+					return
+				}
+				_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
+				if err != nil {
+					utils.ErrorReporting(err, m)
+					return
+				}
+				return
+			}
+		}
+		amount := 0
+		if len(args) == 1 {
+			amount, _ = strconv.Atoi(args[0])
+		}
+		if amount < 1 || amount > purgeLimit {
+			_, err := utils.Bot.Reply(m, fmt.Sprintf("Количество сообщений должно быть числом от 1 до %v.", purgeLimit))
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			return
+		}
+		for _, id := range utils.RecentMessages(m.Chat.ID, target.ID, amount+1) {
+			if id != m.ID && len(ids) < amount {
+				ids = append(ids, id)
+			}
+		}
+	default:
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Пример использования:\nОтправь в ответ на сообщение <code>/purge</code>, чтобы удалить все сообщения от него до команды.\n<code>/purge {количество}</code> - удалить последние сообщения в чате.\n<code>/purge {ID или никнейм} {количество}</code> - удалить последние сообщения пользователя.\nЗа раз можно удалить не больше %v сообщений.", purgeLimit))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	_ = utils.Bot.Delete(m)
+	deleted := purgeMessages(m.Chat, ids)
+	report, err := utils.Bot.Send(m.Chat, fmt.Sprintf("Удалено сообщений: %v.", deleted))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	time.AfterFunc(10*time.Second, func() {
+		_ = utils.Bot.Delete(report)
+	})
+	if target.ID == 0 {
+		return
+	}
+	err = utils.LogModeration(m, m.Sender, &target, "purge", 0, fmt.Sprintf("удалено сообщений: %v", deleted))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+//Delete messages with deleteMessages by 100 at once, one by one if it fails, returns amount of deleted messages
+func purgeMessages(chat *tb.Chat, ids []int) int {
+	var deleted int
+	for start := 0; start < len(ids); start += 100 {
+		end := start + 100
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if start != 0 {
+			time.Sleep(time.Second)
+		}
+		_, err := utils.Bot.Raw("deleteMessages", map[string]interface{}{
+			"chat_id":     chat.Recipient(),
+			"message_ids": ids[start:end],
+		})
+		if err == nil {
+			deleted += end - start
+			continue
+		}
+		//Older Bot API servers don't have deleteMessages
+		for i, id := range ids[start:end] {
+			if i != 0 && i%20 == 0 {
+				time.Sleep(time.Second)
+			}
+			if utils.Bot.Delete(&tb.Message{ID: id, Chat: chat}) == nil {
+				deleted++
+			}
+		}
+	}
+	utils.ForgetMessages(chat.ID, ids)
+	return deleted
+}
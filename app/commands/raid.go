@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strings"
+	"time"
+)
+
+//Manage raid mode on /raid
+func Raid(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	switch {
+	case len(text) == 2 && text[1] == "on":
+		raidOn(m)
+	case len(text) == 2 && text[1] == "off":
+		raidOff(m)
+	case len(text) == 2 && text[1] == "cleanup":
+		raidCleanup(m)
+	case len(text) == 1:
+		raidStatus(m)
+	default:
+		_, err := utils.Bot.Reply(m, "Пример использования:\n<code>/raid</code> - состояние режима рейда\n<code>/raid on</code>\n<code>/raid off</code>\n<code>/raid cleanup</code> - забанить всех вошедших во время рейда")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+	}
+}
+
+func raidStatus(m *tb.Message) {
+	joiners, _, started := utils.RaidJoiners()
+	status := "Режим рейда выключен."
+	if utils.RaidActive() {
+		status = fmt.Sprintf("Режим рейда включён с %v, вошло: %v.", started.Format("02.01.2006 15:04:05"), len(joiners))
+	} else if len(joiners) != 0 {
+		status += fmt.Sprintf("\nВо время последнего рейда с %v вошло: %v.", started.Format("02.01.2006 15:04:05"), len(joiners))
+	}
+	_, err := utils.Bot.Reply(m, status)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+func raidOn(m *tb.Message) {
+	if m.Chat.Username != utils.Config.Telegram.Chat {
+		_, err := utils.Bot.Reply(m, "Режим рейда включается только в основном чате.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	if utils.RaidActive() {
+		_, err := utils.Bot.Reply(m, "Режим рейда уже включён.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	err := utils.RaidStart(m.Chat, fmt.Sprintf("включён вручную %v", utils.UserName(m.Sender)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	_, err = utils.Bot.Reply(m, "Режим рейда включён.")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+func raidOff(m *tb.Message) {
+	if !utils.RaidActive() {
+		_, err := utils.Bot.Reply(m, "Режим рейда не включён.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	err := utils.RaidEnd(fmt.Sprintf("выключен вручную %v", utils.UserName(m.Sender)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	_, err = utils.Bot.Reply(m, "Режим рейда выключен, права в чате восстановлены.")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+func raidCleanup(m *tb.Message) {
+	joiners, chat, _ := utils.RaidJoiners()
+	if len(joiners) == 0 {
+		_, err := utils.Bot.Reply(m, "Во время рейда никто не вошёл.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	context := m
+	if m.Chat.ID != chat.ID {
+		context = &tb.Message{ID: m.ID, Chat: chat}
+	}
+	var banned, skipped int
+	for _, user := range joiners {
+		if user.ID == utils.Bot.Me.ID || utils.IsAdminOrModer(user.Username) {
+			skipped++
+			continue
+		}
+		member, err := utils.Bot.ChatMemberOf(chat, user)
+		if err != nil || member.Role == tb.Kicked {
+			skipped++
+			continue
+		}
+		err = utils.Bot.Ban(chat, &tb.ChatMember{User: user})
+		if err != nil {
+			skipped++
+			continue
+		}
+		err = utils.LogModeration(context, m.Sender, user, "ban", 0, "рейд")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+		}
+		banned++
+		//Stay under Telegram rate limits
+		time.Sleep(100 * time.Millisecond)
+	}
+	_, err := utils.Bot.Reply(m, fmt.Sprintf("Забанено вошедших во время рейда: %v, пропущено: %v.", banned, skipped))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ReportSelector = tb.ReplyMarkup{}
+var ReportButton = ReportSelector.Data("", "report")
+
+var reportActions = map[string]string{
+	"delete":  "сообщение удалено",
+	"warn":    "выдано предупреждение",
+	"mute":    "мьют на 1 час",
+	"ban":     "бан",
+	"dismiss": "жалоба отклонена",
+}
+
+//Send replied message to moderators on /report
+func Report(m *tb.Message) {
+	if m.Chat.Username != utils.Config.Telegram.Chat && !utils.IsAdminOrModer(m.Sender.Username) {
+		return
+	}
+	if m.ReplyTo == nil {
+		_, err := utils.Bot.Reply(m, "Пример использования: отправь в ответ на какое-либо сообщение <code>/report [причина]</code>")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	target := m.ReplyTo.Sender
+	if target.ID == m.Sender.ID || target.ID == utils.Bot.Me.ID || utils.IsAdminOrModer(target.Username) {
+		_, err := utils.Bot.Reply(m, "На это сообщение нельзя пожаловаться.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var report utils.Report
+	result := utils.DB.Where(&utils.Report{ReporterID: m.Sender.ID}).Order("date DESC").Limit(1).Find(&report)
+	cooldown := time.Duration(utils.Config.Reports.Cooldown) * time.Second
+	if result.RowsAffected != 0 && time.Since(report.Date) < cooldown && !utils.IsAdminOrModer(m.Sender.Username) {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Жалобы можно отправлять раз в %v, подожди ещё %v.", utils.FormatDuration(int64(cooldown.Seconds())), utils.FormatDuration(int64((cooldown-time.Since(report.Date)).Seconds()))))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	result = utils.DB.Where(&utils.Report{ChatID: m.Chat.ID, MessageID: m.ReplyTo.ID}).Limit(1).Find(&utils.Report{})
+	if result.RowsAffected != 0 {
+		_, err := utils.Bot.Reply(m, "На это сообщение уже пожаловались.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var reason string
+	if text := strings.SplitN(m.Text, " ", 2); len(text) == 2 {
+		reason = strings.TrimSpace(text[1])
+	}
+	report = utils.Report{
+		Date:       time.Now(),
+		ChatID:     m.Chat.ID,
+		MessageID:  m.ReplyTo.ID,
+		ReporterID: m.Sender.ID,
+		TargetID:   target.ID,
+		Reason:     reason,
+	}
+	result = utils.DB.Create(&report)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	recipients, err := utils.StaffRecipients()
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	text := fmt.Sprintf("Жалоба от %v на %v (<code>%v</code>)%v\n<a href=\"%v\">Сообщение</a>", utils.MentionUser(m.Sender), utils.MentionUser(target), target.ID, utils.ReasonMessage(reason), utils.MessageLink(m.ReplyTo))
+	markup := &tb.ReplyMarkup{}
+	id := strconv.Itoa(report.ID)
+	markup.Inline(
+		markup.Row(markup.Data("🗑 Удалить", ReportButton.Unique, id, "delete"), markup.Data("⚠️ Предупредить", ReportButton.Unique, id, "warn")),
+		markup.Row(markup.Data("🔇 Мьют на 1 час", ReportButton.Unique, id, "mute"), markup.Data("🚫 Бан", ReportButton.Unique, id, "ban")),
+		markup.Row(markup.Data("Отклонить", ReportButton.Unique, id, "dismiss")),
+	)
+	var sent int
+	for _, recipient := range recipients {
+		forwarded, err := utils.Bot.Forward(recipient, m.ReplyTo)
+		if err != nil {
+			continue
+		}
+		_, err = utils.Bot.Send(recipient, text, &tb.SendOptions{ReplyTo: forwarded, ReplyMarkup: markup, DisableWebPagePreview: true})
+		if err != nil {
+			continue
+		}
+		sent++
+	}
+	if sent == 0 {
+		utils.DB.Delete(&report)
+		_, err := utils.Bot.Reply(m, "Не удалось отправить жалобу модераторам, попробуй позже.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	_, err = utils.Bot.Reply(m, "Жалоба отправлена модераторам.")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+//Execute moderator's decision on report button click
+func ReportAction(c *tb.Callback) {
+	data := strings.SplitN(c.Data, "|", 2)
+	if len(data) != 2 {
+		return
+	}
+	if !utils.IsAdminOrModer(c.Sender.Username) {
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: "Обрабатывать жалобы могут только модераторы.", ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	if _, ok := reportActions[data[1]]; !ok {
+		return
+	}
+	var report utils.Report
+	result := utils.DB.First(&report, data[0])
+	if result.RowsAffected == 0 {
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: "Жалоба не найдена.", ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	//Claim report before action, so two moderators can't handle it at the same time
+	result = utils.DB.Model(&utils.Report{}).Where("id = ? AND handler_id = 0", report.ID).Updates(map[string]interface{}{"handler_id": c.Sender.ID, "action": data[1], "handled_at": time.Now()})
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, c.Message)
+		return
+	}
+	if result.RowsAffected != 1 {
+		utils.DB.First(&report, report.ID)
+		var handler tb.User
+		utils.DB.Where(&tb.User{ID: report.HandlerID}).Limit(1).Find(&handler)
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: fmt.Sprintf("Жалоба уже обработана %v: %v.", utils.UserName(&handler), reportActions[report.Action]), ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	report.HandlerID = c.Sender.ID
+	report.Action = data[1]
+	chat, err := utils.Bot.ChatByID(strconv.FormatInt(report.ChatID, 10))
+	if err == nil {
+		context := &tb.Message{ID: report.MessageID, Chat: chat}
+		reason := "жалоба"
+		if report.Reason != "" {
+			reason = fmt.Sprintf("жалоба: %v", report.Reason)
+		}
+		switch data[1] {
+		case "delete":
+			err = utils.Bot.Delete(context)
+		case "warn", "mute", "ban":
+			var member *tb.ChatMember
+			member, err = utils.Bot.ChatMemberOf(chat, &tb.User{ID: report.TargetID})
+			if err != nil {
+				break
+			}
+			switch data[1] {
+			case "warn":
+				err = utils.WarnUser(context, c.Sender, member.User, reason)
+			case "mute":
+				err = utils.PunishUser(context, c.Sender, member.User, "mute", time.Now().Add(time.Hour).Unix(), reason)
+			case "ban":
+				err = utils.PunishUser(context, c.Sender, member.User, "ban", time.Now().Unix(), reason)
+			}
+		}
+	}
+	if err != nil {
+		//Release claim, so report can be handled again
+		utils.DB.Model(&utils.Report{}).Where("id = ?", report.ID).Updates(map[string]interface{}{"handler_id": 0, "action": "", "handled_at": time.Time{}})
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: fmt.Sprintf("Ошибка: %v", err.Error()), ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	err = utils.Bot.Respond(c, &tb.CallbackResponse{Text: reportActions[report.Action]})
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+	_, err = utils.Bot.EditReplyMarkup(c.Message, nil)
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+	_, err = utils.Bot.Reply(c.Message, fmt.Sprintf("%v обработал жалобу: %v.", utils.MentionUser(c.Sender), reportActions[report.Action]))
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+}
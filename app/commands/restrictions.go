@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"html"
+	"time"
+)
+
+//Send list of active mutes, partial ones too, on /mutelist
+func Mutelist(m *tb.Message) {
+	restrictionList(m, "mute", "Активные мьюты", "Сейчас никто не в мьюте.")
+}
+
+//Send list of active bans on /banlist
+func Banlist(m *tb.Message) {
+	restrictionList(m, "ban", "Активные баны", "Сейчас никто не забанен.")
+}
+
+func restrictionList(m *tb.Message, kind string, title string, empty string) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var restrictions []utils.Restriction
+	query := utils.DB.Where("chat_id = ? AND (until_date = 0 OR until_date > ?)", m.Chat.ID, time.Now().Unix())
+	if kind == "ban" {
+		query = query.Where("type = ?", "ban")
+	} else {
+		query = query.Where("type != ?", "ban")
+	}
+	result := query.Order("date DESC").Find(&restrictions)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	if len(restrictions) == 0 {
+		_, err := utils.Bot.Reply(m, empty)
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var userIDs []int
+	for _, restriction := range restrictions {
+		userIDs = append(userIDs, restriction.UserID)
+	}
+	var users []tb.User
+	utils.DB.Where("id IN ?", userIDs).Find(&users)
+	names := make(map[int]string)
+	for _, user := range users {
+		names[user.ID] = utils.MentionUser(&user)
+	}
+	var list = fmt.Sprintf("%v:\n\n", title)
+	for _, restriction := range restrictions {
+		name, ok := names[restriction.UserID]
+		if !ok {
+			name = fmt.Sprintf("<code>%v</code>", restriction.UserID)
+		}
+		if restriction.UntilDate == 0 {
+			list += fmt.Sprintf("%v навсегда", name)
+		} else {
+			list += fmt.Sprintf("%v до %v", name, time.Unix(restriction.UntilDate, 0).Format("02.01.2006 15:04"))
+		}
+		if mode, ok := utils.MuteModes[restriction.Type]; ok {
+			list += fmt.Sprintf(" (запрет: %v)", mode)
+		}
+		if restriction.Reason != "" {
+			list += fmt.Sprintf(": %v", html.EscapeString(restriction.Reason))
+		}
+		list += "\n"
+		if len(list) > 3900 {
+			_, err := utils.Bot.Reply(m, list)
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			list = ""
+		}
+	}
+	if list == "" {
+		return
+	}
+	_, err := utils.Bot.Reply(m, list)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
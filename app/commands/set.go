@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/NexonSU/telegram-go-chatbot/app/utils"
 	tb "gopkg.in/tucnak/telebot.v2"
@@ -30,6 +31,23 @@ func Set(m *tb.Message) {
 	} else if m.ReplyTo != nil && len(text) == 2 {
 		get.Caption = m.ReplyTo.Caption
 		switch {
+		case m.ReplyTo.AlbumID != "" && (m.ReplyTo.Photo != nil || m.ReplyTo.Video != nil):
+			utils.RememberAlbumItem(m.ReplyTo)
+			items := utils.AlbumItems(m.ReplyTo.AlbumID)
+			data, err := json.Marshal(items)
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			get.Type = "Album"
+			get.Data = string(data)
+			get.Caption = ""
+			for _, item := range items {
+				if item.Caption != "" {
+					get.Caption = item.Caption
+					break
+				}
+			}
 		case m.ReplyTo.Animation != nil:
 			get.Type = "Animation"
 			get.Data = m.ReplyTo.Animation.FileID
@@ -45,6 +63,28 @@ func Set(m *tb.Message) {
 		case m.ReplyTo.Voice != nil:
 			get.Type = "Voice"
 			get.Data = m.ReplyTo.Voice.FileID
+		case m.ReplyTo.Sticker != nil:
+			get.Type = "Sticker"
+			get.Data = m.ReplyTo.Sticker.FileID
+		case m.ReplyTo.VideoNote != nil:
+			get.Type = "VideoNote"
+			get.Data = m.ReplyTo.VideoNote.FileID
+		case m.ReplyTo.Poll != nil:
+			poll := *m.ReplyTo.Poll
+			poll.ID = ""
+			poll.VoterCount = 0
+			poll.Closed = false
+			poll.Options = append([]tb.PollOption{}, poll.Options...)
+			for i := range poll.Options {
+				poll.Options[i].VoterCount = 0
+			}
+			data, err := json.Marshal(poll)
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			get.Type = "Poll"
+			get.Data = string(data)
 		case m.ReplyTo.Document != nil:
 			get.Type = "Document"
 			get.Data = m.ReplyTo.Document.FileID
@@ -60,8 +100,10 @@ func Set(m *tb.Message) {
 			return
 		}
 	}
+	//Replaced get keeps its category, but not old buttons and expiry date
 	result := utils.DB.Clauses(clause.OnConflict{
-		UpdateAll: true,
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"type", "data", "caption", "buttons", "expires_at"}),
 	}).Create(get)
 	if result.Error != nil {
 		utils.ErrorReporting(result.Error, m)
@@ -13,8 +13,11 @@ func Slap(m *tb.Message) {
 	if utils.IsAdminOrModer(m.Sender.Username) {
 		action = "дал отцовского леща"
 	}
-	target, _, err := utils.FindUserInMessage(*m)
+	target, _, _, err := utils.FindUserInMessage(*m)
 	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
 		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
 		if err != nil {
 			utils.ErrorReporting(err, m)
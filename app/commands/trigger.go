@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//Manage keyword triggers on /trigger
+func Trigger(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	switch {
+	case len(text) > 5 && (text[1] == "add" || text[1] == "word"):
+		triggerAdd(m, text)
+	case len(text) == 2 && text[1] == "list":
+		triggerList(m)
+	case len(text) == 3 && text[1] == "del":
+		triggerDel(m, text[2])
+	default:
+		_, err := utils.Bot.Reply(m, "Пример использования:\n<code>/trigger add {гет} {задержка в секундах} {вероятность в %} {регулярка}</code>\n<code>/trigger word {гет} {задержка в секундах} {вероятность в %} {слово или фраза}</code>\n<code>/trigger list</code>\n<code>/trigger del {ID}</code>\nТриггер срабатывает только в чате, где был добавлен.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+	}
+}
+
+func triggerAdd(m *tb.Message, text []string) {
+	var get utils.Get
+	var trigger utils.Trigger
+	var err error
+	trigger.GetName = strings.ToLower(text[2])
+	trigger.ChatID = m.Chat.ID
+	trigger.Pattern = strings.Join(text[5:], " ")
+	trigger.Literal = text[1] == "word"
+	trigger.Cooldown, err = strconv.Atoi(text[3])
+	if err != nil || trigger.Cooldown < 0 {
+		_, err := utils.Bot.Reply(m, "Задержка должна быть целым неотрицательным числом секунд.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	trigger.Probability, err = strconv.Atoi(strings.TrimSuffix(text[4], "%"))
+	if err != nil || trigger.Probability < 1 || trigger.Probability > 100 {
+		_, err := utils.Bot.Reply(m, "Вероятность должна быть целым числом от 1 до 100.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	_, err = regexp.Compile("(?i)" + trigger.Pattern)
+	if err != nil && !trigger.Literal {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось разобрать регулярку:\n<code>%v</code>", html.EscapeString(err.Error())))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	result := utils.DB.Where(&utils.Get{Name: trigger.GetName}).First(&get)
+	if result.RowsAffected == 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Гет <code>%v</code> не найден.", trigger.GetName))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	result = utils.DB.Create(&trigger)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось сохранить триггер:\n<code>%v</code>.", result.Error))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	_, err = utils.Bot.Reply(m, fmt.Sprintf("Триггер #%v сохранён: <code>%v</code> → <code>%v</code>.", trigger.ID, html.EscapeString(trigger.Pattern), trigger.GetName))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+func triggerList(m *tb.Message) {
+	var triggers []utils.Trigger
+	result := utils.DB.Where(&utils.Trigger{ChatID: m.Chat.ID}).Find(&triggers)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	if len(triggers) == 0 {
+		_, err := utils.Bot.Reply(m, "В этом чате нет триггеров.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var list string
+	for _, trigger := range triggers {
+		kind := "регулярка"
+		if trigger.Literal {
+			kind = "слово"
+		}
+		list += fmt.Sprintf("#%v %v <code>%v</code> → <code>%v</code>, задержка %v сек., вероятность %v%%, срабатываний: %v\n", trigger.ID, kind, html.EscapeString(trigger.Pattern), trigger.GetName, trigger.Cooldown, trigger.Probability, trigger.Hits)
+		if len(list) > 3900 {
+			_, err := utils.Bot.Reply(m, list)
+			if err != nil {
+				utils.ErrorReporting(err, m)
+				return
+			}
+			list = ""
+		}
+	}
+	if list == "" {
+		return
+	}
+	_, err := utils.Bot.Reply(m, list)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+func triggerDel(m *tb.Message, id string) {
+	triggerID, err := strconv.Atoi(strings.TrimPrefix(id, "#"))
+	if err != nil {
+		_, err := utils.Bot.Reply(m, "ID триггера должен быть числом.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	result := utils.DB.Where(&utils.Trigger{ChatID: m.Chat.ID}).Delete(&utils.Trigger{ID: triggerID})
+	if result.RowsAffected != 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Триггер #%v удалён.", triggerID))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+	} else {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Триггер #%v не найден.", triggerID))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strings"
+)
+
+//Lift newcomer probation on /trust
+func Trust(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	if (m.ReplyTo == nil && len(text) < 2) || (m.ReplyTo != nil && len(text) != 1) {
+		_, err := utils.Bot.Reply(m, "Пример использования: <code>/trust {ID или никнейм}</code>\nИли отправь в ответ на какое-либо сообщение <code>/trust</code>")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	target, _, _, err := utils.FindUserInMessage(*m)
+	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	result := utils.DB.Delete(&utils.Probation{UserID: target.ID, ChatID: m.Chat.ID})
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	if result.RowsAffected == 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("%v не на испытательном сроке.", utils.UserFullName(&target)))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	_, err = utils.Bot.Reply(m, fmt.Sprintf("С %v снят испытательный срок, теперь можно отправлять ссылки.", utils.MentionUser(&target)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
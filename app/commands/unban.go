@@ -22,7 +22,7 @@ func Unban(m *tb.Message) {
 	}
 	var target tb.User
 	var text = strings.Split(m.Text, " ")
-	if (m.ReplyTo == nil && len(text) != 2) || (m.ReplyTo != nil && len(text) != 1) {
+	if (m.ReplyTo == nil && len(text) < 2) || (m.ReplyTo != nil && len(text) != 1) {
 		_, err := utils.Bot.Reply(m, "Пример использования: <code>/unban {ID или никнейм}</code>\nИли отправь в ответ на какое-либо сообщение <code>/unban</code>")
 		if err != nil {
 			utils.ErrorReporting(err, m)
@@ -30,8 +30,11 @@ func Unban(m *tb.Message) {
 		}
 		return
 	}
-	target, _, err := utils.FindUserInMessage(*m)
+	target, _, _, err := utils.FindUserInMessage(*m)
 	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
 		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
 		if err != nil {
 			utils.ErrorReporting(err, m)
@@ -48,6 +51,10 @@ func Unban(m *tb.Message) {
 		}
 		return
 	}
+	err = utils.LogModeration(m, m.Sender, &target, "unban", 0, "")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
 	_, err = utils.Bot.Reply(m, fmt.Sprintf("<a href=\"tg://user?id=%v\">%v</a> разбанен.", target.ID, utils.UserFullName(&target)))
 	if err != nil {
 		utils.ErrorReporting(err, m)
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"gorm.io/gorm/clause"
+	"strconv"
+	"time"
+)
+
+//Reverse action for moderation log
+var undoLogActions = map[string]string{
+	"ban":  "unban",
+	"kick": "unban",
+	"mute": "unmute",
+	"kill": "revive",
+	"warn": "unwarn",
+}
+
+//Revert moderation action on undo button click
+func Undo(c *tb.Callback) {
+	if !utils.IsAdminOrModer(c.Sender.Username) {
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: "Отменять действия могут только модераторы.", ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	id, err := strconv.Atoi(c.Data)
+	if err != nil {
+		return
+	}
+	action, ok := utils.TakeUndo(id)
+	if !ok {
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: "Время на отмену истекло.", ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		_, err = utils.Bot.EditReplyMarkup(c.Message, nil)
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	switch action.Action {
+	case "ban", "kick":
+		err = utils.UnbanIfBanned(action.Chat, action.Target)
+	case "mute":
+		err = utils.RestoreRights(action.Chat, &tb.ChatMember{User: action.Target})
+	case "kill":
+		err = utils.RestoreRights(action.Chat, &tb.ChatMember{User: action.Target})
+		if err == nil {
+			err = undoDeath(action.Target)
+		}
+	case "warn":
+		now := time.Now()
+		result := utils.DB.Model(&utils.Warning{}).Where("id = ?", action.WarningID).Updates(utils.Warning{RevokerID: c.Sender.ID, RevokedAt: &now})
+		err = result.Error
+		switch {
+		case err != nil:
+		case action.Punishment == "mute":
+			err = utils.RestoreRights(action.Chat, &tb.ChatMember{User: action.Target})
+		case action.Punishment == "ban", action.Punishment == "kick":
+			err = utils.UnbanIfBanned(action.Chat, action.Target)
+		}
+	}
+	if err != nil {
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: fmt.Sprintf("Ошибка: %v", err.Error()), ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	err = utils.Bot.Respond(c, &tb.CallbackResponse{Text: "Действие отменено."})
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+	}
+	_, err = utils.Bot.EditReplyMarkup(c.Message, nil)
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+	}
+	_, err = utils.Bot.Reply(c.Message, fmt.Sprintf("%v отменил действие: %v для %v.", utils.MentionUser(c.Sender), utils.ModerationActionNames[action.Action], utils.MentionUser(action.Target)))
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+	}
+	reason := fmt.Sprintf("отмена: %v", utils.ModerationActionNames[action.Action])
+	err = utils.LogModeration(c.Message, c.Sender, action.Target, undoLogActions[action.Action], 0, reason)
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+	if action.Punishment == "ban" || action.Punishment == "mute" {
+		err = utils.LogModeration(c.Message, c.Sender, action.Target, undoLogActions[action.Punishment], 0, reason)
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+			return
+		}
+	}
+}
+
+//Take back death, given by /kill
+func undoDeath(target *tb.User) error {
+	var duelist utils.Duelist
+	result := utils.DB.Model(utils.Duelist{}).Where(target.ID).First(&duelist)
+	if result.RowsAffected == 0 || duelist.Deaths == 0 {
+		return nil
+	}
+	duelist.Deaths--
+	result = utils.DB.Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(duelist)
+	return result.Error
+}
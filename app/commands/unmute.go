@@ -5,7 +5,6 @@ import (
 	"github.com/NexonSU/telegram-go-chatbot/app/utils"
 	tb "gopkg.in/tucnak/telebot.v2"
 	"strings"
-	"time"
 )
 
 //Unmute user on /unmute
@@ -23,7 +22,7 @@ func Unmute(m *tb.Message) {
 	}
 	var target tb.User
 	var text = strings.Split(m.Text, " ")
-	if (m.ReplyTo == nil && len(text) != 2) || (m.ReplyTo != nil && len(text) != 1) {
+	if (m.ReplyTo == nil && len(text) < 2) || (m.ReplyTo != nil && len(text) != 1) {
 		_, err := utils.Bot.Reply(m, "Пример использования: <code>/unmute {ID или никнейм}</code>\nИли отправь в ответ на какое-либо сообщение <code>/unmute</code>")
 		if err != nil {
 			utils.ErrorReporting(err, m)
@@ -31,8 +30,11 @@ func Unmute(m *tb.Message) {
 		}
 		return
 	}
-	target, _, err := utils.FindUserInMessage(*m)
+	target, _, _, err := utils.FindUserInMessage(*m)
 	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
 		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
 		if err != nil {
 			utils.ErrorReporting(err, m)
@@ -49,13 +51,7 @@ func Unmute(m *tb.Message) {
 		}
 		return
 	}
-	TargetChatMember.CanSendMessages = true
-	TargetChatMember.CanSendMedia = true
-	TargetChatMember.CanSendPolls = true
-	TargetChatMember.CanSendOther = true
-	TargetChatMember.CanAddPreviews = true
-	TargetChatMember.RestrictedUntil = time.Now().Unix() + 60
-	err = utils.Bot.Restrict(m.Chat, TargetChatMember)
+	err = utils.RestoreRights(m.Chat, TargetChatMember)
 	if err != nil {
 		_, err := utils.Bot.Reply(m, fmt.Sprintf("Ошибка снятия ограничения пользователя:\n<code>%v</code>", err.Error()))
 		if err != nil {
@@ -64,6 +60,10 @@ func Unmute(m *tb.Message) {
 		}
 		return
 	}
+	err = utils.LogModeration(m, m.Sender, &target, "unmute", 0, "")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
 	_, err = utils.Bot.Reply(m, fmt.Sprintf("<a href=\"tg://user?id=%v\">%v</a> снова может отправлять сообщения в чат.", target.ID, utils.UserFullName(&target)))
 	if err != nil {
 		utils.ErrorReporting(err, m)
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strings"
+	"time"
+)
+
+//Remove user's last active warning on /unwarn
+func Unwarn(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	if m.ReplyTo == nil && len(text) < 2 {
+		_, err := utils.Bot.Reply(m, "Пример использования: <code>/unwarn {ID или никнейм} [причина]</code>\nИли отправь в ответ на какое-либо сообщение <code>/unwarn [причина]</code>")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	target, args, err := utils.FindUserWithArgs(*m, 0)
+	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	reason := strings.Join(args, " ")
+	warnings, err := utils.ActiveWarnings(target.ID)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	if len(warnings) == 0 {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("У %v нет активных предупреждений.", utils.UserFullName(&target)))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	now := time.Now()
+	result := utils.DB.Model(&warnings[len(warnings)-1]).Updates(utils.Warning{RevokerID: m.Sender.ID, RevokedAt: &now})
+	if result.Error != nil {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось снять предупреждение:\n<code>%v</code>", result.Error))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	err = utils.LogModeration(m, m.Sender, &target, "unwarn", 0, reason)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	_, err = utils.Bot.Reply(m, fmt.Sprintf("С %v снято предупреждение, осталось %v.%v", utils.MentionUser(&target), utils.WarnAmountMessage(len(warnings)-1), utils.ReasonMessage(reason)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strconv"
+	"strings"
+)
+
+//Repeat command with user, chosen by button
+func UserChoice(c *tb.Callback) {
+	data := strings.Split(c.Data, "|")
+	if len(data) != 2 {
+		return
+	}
+	id, err := strconv.Atoi(data[0])
+	if err != nil {
+		return
+	}
+	m, err := utils.TakeUserChoice(id, c.Sender, data[1])
+	if err != nil {
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: fmt.Sprintf("%v.", err.Error()), ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	err = utils.Bot.Respond(c, &tb.CallbackResponse{})
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+	}
+	err = utils.Bot.Delete(c.Message)
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+	}
+	utils.Bot.ProcessUpdate(tb.Update{Message: &m})
+}
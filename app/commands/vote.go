@@ -0,0 +1,252 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var VoteSelector = tb.ReplyMarkup{}
+var VoteButton = VoteSelector.Data("", "vote")
+
+type communityVote struct {
+	ID      int
+	Action  string
+	Target  *tb.User
+	Starter *tb.User
+	//replied message of target, context for punishment
+	Context   *tb.Message
+	Message   *tb.Message
+	UntilDate int64
+	EndsAt    time.Time
+	//voter ID and whether they voted for
+	Voters map[int]bool
+	Closed bool
+}
+
+var votes = struct {
+	sync.Mutex
+	list     map[int]*communityVote
+	last     int
+	starters map[int]time.Time
+	targets  map[int]time.Time
+}{list: make(map[int]*communityVote), starters: make(map[int]time.Time), targets: make(map[int]time.Time)}
+
+//Start vote for mute of replied user on /votemute
+func Votemute(m *tb.Message) {
+	startVote(m, "mute")
+}
+
+//Start vote for kick of replied user on /votekick
+func Votekick(m *tb.Message) {
+	startVote(m, "kick")
+}
+
+func startVote(m *tb.Message, action string) {
+	if m.Chat.Username != utils.Config.Telegram.Chat {
+		return
+	}
+	if !utils.Config.Votes.Enabled {
+		_, err := utils.Bot.Reply(m, "Голосования выключены.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	if m.ReplyTo == nil {
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Пример использования: отправь в ответ на какое-либо сообщение <code>/vote%v</code>", action))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	target := m.ReplyTo.Sender
+	if target.ID == m.Sender.ID || target.ID == utils.Bot.Me.ID || utils.IsAdminOrModer(target.Username) {
+		_, err := utils.Bot.Reply(m, "Против этого пользователя нельзя голосовать.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	staff := utils.IsAdminOrModer(m.Sender.Username)
+	if !staff && !utils.VoteEligible(m.Chat, m.Sender) {
+		_, err := utils.Bot.Reply(m, "Голосовать могут только участники, которые давно пишут в чат или написали достаточно сообщений.")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	cooldown := time.Duration(utils.Config.Votes.Cooldown) * time.Second
+	votes.Lock()
+	var refusal string
+	switch {
+	case !staff && time.Since(votes.starters[m.Sender.ID]) < cooldown:
+		refusal = fmt.Sprintf("Голосование можно запускать раз в %v, подожди ещё %v.", utils.FormatDuration(int64(cooldown.Seconds())), utils.FormatDuration(int64((cooldown - time.Since(votes.starters[m.Sender.ID])).Seconds())))
+	case time.Since(votes.targets[target.ID]) < cooldown:
+		refusal = "Против этого пользователя уже недавно голосовали."
+	}
+	if refusal != "" {
+		votes.Unlock()
+		_, err := utils.Bot.Reply(m, refusal)
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	for id, e := range votes.list {
+		if e.Closed {
+			delete(votes.list, id)
+		}
+	}
+	votes.last++
+	vote := &communityVote{
+		ID:      votes.last,
+		Action:  action,
+		Target:  target,
+		Starter: m.Sender,
+		Context: m.ReplyTo,
+		EndsAt:  time.Now().Add(time.Duration(utils.Config.Votes.Window) * time.Second),
+		Voters:  map[int]bool{m.Sender.ID: true},
+	}
+	if action == "mute" {
+		vote.UntilDate = time.Now().Unix()
+		duration, err := utils.ParseDuration(utils.Config.Votes.MuteDuration)
+		if err == nil {
+			vote.UntilDate += int64(duration.Seconds())
+		}
+	}
+	votes.list[vote.ID] = vote
+	votes.starters[m.Sender.ID] = time.Now()
+	votes.targets[target.ID] = time.Now()
+	text, markup := voteMessage(vote)
+	votes.Unlock()
+	message, err := utils.Bot.Reply(m.ReplyTo, text, markup)
+	if err != nil {
+		votes.Lock()
+		vote.Closed = true
+		votes.Unlock()
+		utils.ErrorReporting(err, m)
+		return
+	}
+	votes.Lock()
+	vote.Message = message
+	votes.Unlock()
+	time.AfterFunc(time.Until(vote.EndsAt), func() {
+		closeVote(vote)
+	})
+}
+
+//Votes for and against, vote must be locked
+func voteTally(vote *communityVote) (int, int) {
+	var yes, no int
+	for _, e := range vote.Voters {
+		if e {
+			yes++
+		} else {
+			no++
+		}
+	}
+	return yes, no
+}
+
+//Vote question, current tally and buttons, vote must be locked
+func voteMessage(vote *communityVote) (string, *tb.ReplyMarkup) {
+	yes, no := voteTally(vote)
+	question := fmt.Sprintf("исключить %v из чата", utils.MentionUser(vote.Target))
+	if vote.Action == "mute" {
+		question = fmt.Sprintf("замьютить %v%v", utils.MentionUser(vote.Target), utils.RestrictionTimeMessage(vote.UntilDate))
+	}
+	text := fmt.Sprintf("🗳 Голосование: %v?\nИнициатор: %v\nНужно голосов «за»: %v, голосование до %v.", question, utils.UserName(vote.Starter), utils.Config.Votes.Voters, vote.EndsAt.Format("15:04"))
+	markup := &tb.ReplyMarkup{}
+	id := strconv.Itoa(vote.ID)
+	markup.Inline(markup.Row(
+		markup.Data(fmt.Sprintf("👍 За (%v)", yes), VoteButton.Unique, id, "yes"),
+		markup.Data(fmt.Sprintf("👎 Против (%v)", no), VoteButton.Unique, id, "no"),
+	))
+	return text, markup
+}
+
+//Count vote on button click and apply punishment, when enough voters agree
+func VoteAction(c *tb.Callback) {
+	data := strings.Split(c.Data, "|")
+	if len(data) != 2 {
+		return
+	}
+	id, err := strconv.Atoi(data[0])
+	if err != nil {
+		return
+	}
+	votes.Lock()
+	vote, ok := votes.list[id]
+	var refusal string
+	switch {
+	case !ok || vote.Closed || vote.Message == nil:
+		refusal = "Голосование завершено."
+	case c.Sender.ID == vote.Target.ID:
+		refusal = "Ты не можешь участвовать в этом голосовании."
+	case !utils.IsAdminOrModer(c.Sender.Username) && !utils.VoteEligible(vote.Message.Chat, c.Sender):
+		refusal = "Голосовать могут только участники, которые давно пишут в чат или написали достаточно сообщений."
+	}
+	if refusal != "" {
+		votes.Unlock()
+		err := utils.Bot.Respond(c, &tb.CallbackResponse{Text: refusal, ShowAlert: true})
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	vote.Voters[c.Sender.ID] = data[1] == "yes"
+	yes, no := voteTally(vote)
+	passed := yes >= utils.Config.Votes.Voters && yes > no
+	if passed {
+		vote.Closed = true
+	}
+	text, markup := voteMessage(vote)
+	votes.Unlock()
+	err = utils.Bot.Respond(c, &tb.CallbackResponse{Text: "Голос учтён."})
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+	}
+	if !passed {
+		_, err = utils.Bot.Edit(vote.Message, text, markup)
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
+		return
+	}
+	_, err = utils.Bot.Edit(vote.Message, fmt.Sprintf("%v\n\nРешение принято: %v за, %v против.", text, yes, no))
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+	}
+	err = utils.PunishUser(vote.Context, utils.Bot.Me, vote.Target, vote.Action, vote.UntilDate, fmt.Sprintf("голосование: %v за, %v против", yes, no))
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+		return
+	}
+}
+
+//Close vote, that didn't get enough voters in time
+func closeVote(vote *communityVote) {
+	votes.Lock()
+	if vote.Closed || vote.Message == nil {
+		votes.Unlock()
+		return
+	}
+	vote.Closed = true
+	yes, no := voteTally(vote)
+	text, _ := voteMessage(vote)
+	votes.Unlock()
+	_, err := utils.Bot.Edit(vote.Message, fmt.Sprintf("%v\n\nГолосование не набрало голосов: %v за, %v против.", text, yes, no))
+	if err != nil {
+		utils.ErrorReporting(err, vote.Message)
+	}
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"html"
+	"strings"
+)
+
+//Send user's active warnings on /warns
+func Warns(m *tb.Message) {
+	if !utils.IsAdminOrModer(m.Sender.Username) {
+		if m.Chat.Username != utils.Config.Telegram.Chat {
+			return
+		}
+		_, err := utils.Bot.Reply(m, &tb.Animation{File: tb.File{FileID: "CgACAgIAAx0CQvXPNQABHGrDYIBIvDLiVV6ZMPypWMi_NVDkoFQAAq4LAAIwqQlIQT82LRwIpmoeBA"}})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	var text = strings.Split(m.Text, " ")
+	if (m.ReplyTo == nil && len(text) < 2) || (m.ReplyTo != nil && len(text) != 1) {
+		_, err := utils.Bot.Reply(m, "Пример использования: <code>/warns {ID или никнейм}</code>\nИли отправь в ответ на какое-либо сообщение <code>/warns</code>")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	target, _, _, err := utils.FindUserInMessage(*m)
+	if err != nil {
+		if err == utils.ErrUserChoice {
+			return
+		}
+		_, err := utils.Bot.Reply(m, fmt.Sprintf("Не удалось определить пользователя:\n<code>%v</code>", err.Error()))
+		if err != nil {
+			utils.ErrorReporting(err, m)
+			return
+		}
+		return
+	}
+	warnings, err := utils.ActiveWarnings(target.ID)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	_, err = utils.Bot.Reply(m, fmt.Sprintf("У %v %v.%v", utils.UserFullName(&target), utils.WarnAmountMessage(len(warnings)), warningsList(warnings, true)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
+
+//List warnings with their expiry dates
+func warningsList(warnings []utils.Warning, withIssuer bool) string {
+	var list string
+	issuers := make(map[int]string)
+	if withIssuer {
+		var ids []int
+		for _, warning := range warnings {
+			ids = append(ids, warning.IssuerID)
+		}
+		var users []tb.User
+		utils.DB.Where("id IN ?", ids).Find(&users)
+		for _, user := range users {
+			issuers[user.ID] = utils.UserName(&user)
+		}
+	}
+	for i, warning := range warnings {
+		list += fmt.Sprintf("\n%v. %v, истекает %v", i+1, warning.Date.Format("02.01.2006"), warning.ExpiresAt.Format("02.01.2006 15:04"))
+		if issuer, ok := issuers[warning.IssuerID]; ok {
+			list += fmt.Sprintf(", выдал %v", html.EscapeString(issuer))
+		}
+		if warning.Reason != "" {
+			list += fmt.Sprintf(": %v", html.EscapeString(warning.Reason))
+		}
+	}
+	return list
+}
@@ -132,6 +132,10 @@ func Accept(c *tb.Callback) {
 			utils.ErrorReporting(err, c.Message)
 			return
 		}
+		err = utils.TrackRestriction(c.Message.Chat, player, utils.Bot.Me, "mute", PlayerChatMember.RestrictedUntil, "русская рулетка")
+		if err != nil {
+			utils.ErrorReporting(err, c.Message)
+		}
 		_, err = utils.Bot.Edit(message, fmt.Sprintf("%v😈 Наводит револьвер на %v и стреляет.\nЯ хз как это объяснить, но %v победитель!\n%v отправился на респавн на %v0 минут.", prefix, utils.MentionUser(player), utils.MentionUser(victim), utils.MentionUser(player), duelist.Deaths))
 		if err != nil {
 			utils.ErrorReporting(err, c.Message)
@@ -182,6 +186,10 @@ func Accept(c *tb.Callback) {
 		utils.ErrorReporting(err, c.Message)
 		return
 	}
+	err = utils.TrackRestriction(c.Message.Chat, victim, utils.Bot.Me, "mute", VictimChatMember.RestrictedUntil, "русская рулетка")
+	if err != nil {
+		utils.ErrorReporting(err, c.Message)
+	}
 	_, err = utils.Bot.Edit(message, fmt.Sprintf("%v\nПобедитель дуэли: %v.\n%v отправился на респавн на %v0 минут.", prefix, utils.MentionUser(player), utils.MentionUser(victim), VictimDuelist.Deaths))
 	if err != nil {
 		utils.ErrorReporting(err, c.Message)
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+//Remember media group items, count activity, check probation, link mute, blacklist and flood on incoming media
+func OnMedia(m *tb.Message) {
+	utils.RememberAlbumItem(m)
+	err := utils.CountActivity(m)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	if checkProbation(m) || checkLinkMute(m) || checkBlacklist(m) {
+		return
+	}
+	checkFlood(m, "media", utils.Config.Antiflood.Media)
+}
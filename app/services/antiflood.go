@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+type floodEntry struct {
+	Date      time.Time
+	MessageID int
+	AlbumID   string
+}
+
+var floodWindows = struct {
+	sync.Mutex
+	list  map[string][]floodEntry
+	muted map[string]time.Time
+}{list: make(map[string][]floodEntry), muted: make(map[string]time.Time)}
+
+var floodKinds = map[string]string{
+	"messages": "сообщения",
+	"stickers": "стикеры",
+	"media":    "медиа",
+}
+
+func floodExempt(user *tb.User) bool {
+	if user.ID == utils.Bot.Me.ID || utils.IsAdminOrModer(user.Username) {
+		return true
+	}
+	for _, username := range utils.Config.Antiflood.Trusted {
+		if username == user.Username {
+			return true
+		}
+	}
+	return false
+}
+
+//Count message in sender's sliding window and mute sender if limit is exceeded, true if sender is flooding
+func checkFlood(m *tb.Message, kind string, limit int) bool {
+	if !utils.Config.Antiflood.Enabled || m.Private() || m.Sender == nil || floodExempt(m.Sender) {
+		return false
+	}
+	window := time.Duration(utils.Config.Antiflood.Window) * time.Second
+	user := fmt.Sprintf("%v:%v", m.Chat.ID, m.Sender.ID)
+	key := fmt.Sprintf("%v:%v", user, kind)
+	floodWindows.Lock()
+	for k, entries := range floodWindows.list {
+		if len(entries) == 0 || time.Since(entries[len(entries)-1].Date) > window {
+			delete(floodWindows.list, k)
+		}
+	}
+	for k, date := range floodWindows.muted {
+		if time.Since(date) > window {
+			delete(floodWindows.muted, k)
+		}
+	}
+	if time.Since(floodWindows.muted[user]) < window {
+		floodWindows.Unlock()
+		if utils.Config.Antiflood.DeleteMessages {
+			_ = utils.Bot.Delete(m)
+		}
+		return true
+	}
+	var entries []floodEntry
+	for _, e := range floodWindows.list[key] {
+		if time.Since(e.Date) < window {
+			entries = append(entries, e)
+		}
+		//Album is sent as many messages at once, count it only once
+		if m.AlbumID != "" && e.AlbumID == m.AlbumID {
+			floodWindows.Unlock()
+			return false
+		}
+	}
+	entries = append(entries, floodEntry{Date: time.Now(), MessageID: m.ID, AlbumID: m.AlbumID})
+	if len(entries) <= limit {
+		floodWindows.list[key] = entries
+		floodWindows.Unlock()
+		return false
+	}
+	delete(floodWindows.list, key)
+	floodWindows.muted[user] = time.Now()
+	floodWindows.Unlock()
+	floodMute(m, kind, entries)
+	return true
+}
+
+//Mute flooder, each repeat in 24 hours doubles mute duration
+func floodMute(m *tb.Message, kind string, entries []floodEntry) {
+	var repeats int64
+	result := utils.DB.Model(&utils.ModerationAction{}).Where("chat_id = ? AND target_id = ? AND action = ? AND date > ?", m.Chat.ID, m.Sender.ID, "flood", time.Now().Add(-24*time.Hour)).Count(&repeats)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+	}
+	duration, err := utils.ParseDuration(utils.Config.Antiflood.MuteDuration)
+	if err != nil || duration == 0 {
+		duration = 10 * time.Minute
+	}
+	//Each repeat doubles mute, but longer than 366 days would be forever
+	for ; repeats > 0 && duration < utils.MaxDuration; repeats-- {
+		duration *= 2
+	}
+	if duration > utils.MaxDuration {
+		duration = utils.MaxDuration
+	}
+	untildate := time.Now().Add(duration).Unix()
+	member, err := utils.Bot.ChatMemberOf(m.Chat, m.Sender)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	err = utils.SaveRights(m.Chat, member)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	member.CanSendMessages = false
+	member.RestrictedUntil = untildate
+	err = utils.Bot.Restrict(m.Chat, member)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+	if utils.Config.Antiflood.DeleteMessages {
+		for _, e := range entries {
+			_ = utils.Bot.Delete(&tb.Message{ID: e.MessageID, Chat: m.Chat})
+		}
+	}
+	reason := fmt.Sprintf("флуд (%v: %v за %v)", floodKinds[kind], len(entries), utils.FormatDuration(int64(utils.Config.Antiflood.Window)))
+	_, err = utils.Bot.Send(m.Chat, fmt.Sprintf("Пользователь %v больше не может отправлять сообщения%v.%v", utils.MentionUser(m.Sender), utils.RestrictionTimeMessage(untildate), utils.ReasonMessage(reason)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	err = utils.LogModeration(m, utils.Bot.Me, m.Sender, "flood", untildate, reason)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return
+	}
+}
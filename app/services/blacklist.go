@@ -0,0 +1,94 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"gorm.io/gorm"
+)
+
+//Delete message matching chat blacklist and punish sender, true if message was deleted
+func checkBlacklist(m *tb.Message) bool {
+	if m.Private() || m.Sender == nil || m.Sender.ID == utils.Bot.Me.ID || utils.IsAdminOrModer(m.Sender.Username) {
+		return false
+	}
+	text := m.Text
+	if m.Caption != "" {
+		text = m.Caption
+	}
+	if text == "" {
+		return false
+	}
+	entry, err := utils.BlacklistMatch(m.Chat.ID, text)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return false
+	}
+	if entry == nil {
+		return false
+	}
+	if !blacklistRemember(m) {
+		return false
+	}
+	result := utils.DB.Model(entry).Update("hits", gorm.Expr("hits + 1"))
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+	}
+	err = utils.Bot.Delete(m)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return false
+	}
+	reason := fmt.Sprintf("чёрный список #%v", entry.ID)
+	switch entry.Action {
+	case "warn":
+		err = utils.WarnUser(m, utils.Bot.Me, m.Sender, reason)
+	case "mute", "ban":
+		duration, _ := utils.ParseDuration(entry.Duration)
+		if entry.Action == "mute" && duration == 0 {
+			duration = time.Hour
+		}
+		err = utils.PunishUser(m, utils.Bot.Me, m.Sender, entry.Action, time.Now().Add(duration).Unix(), reason)
+	default:
+		err = utils.LogModeration(m, utils.Bot.Me, m.Sender, "delete", 0, reason)
+	}
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	return true
+}
+
+//Messages punished by blacklist, so their edits aren't punished again
+var blacklistPunished = struct {
+	sync.Mutex
+	list map[string]time.Time
+}{list: make(map[string]time.Time)}
+
+//Remember punished message, false if it was already punished
+func blacklistRemember(m *tb.Message) bool {
+	key := fmt.Sprintf("%v|%v", m.Chat.ID, m.ID)
+	blacklistPunished.Lock()
+	defer blacklistPunished.Unlock()
+	if _, ok := blacklistPunished.list[key]; ok {
+		return false
+	}
+	//Telegram allows to edit messages only for 48 hours
+	for e, date := range blacklistPunished.list {
+		if time.Since(date) > 48*time.Hour {
+			delete(blacklistPunished.list, e)
+		}
+	}
+	blacklistPunished.list[key] = time.Now()
+	return true
+}
+
+//Check link mute and blacklist on edited message
+func OnEdited(m *tb.Message) {
+	if checkLinkMute(m) {
+		return
+	}
+	checkBlacklist(m)
+}
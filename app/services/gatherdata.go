@@ -5,10 +5,30 @@ import (
 	tb "gopkg.in/tucnak/telebot.v2"
 )
 
-//Gather user data on incoming text message
+//Gather user data and activity, check probation, link mute, blacklist, flood and triggers on incoming text message
 func OnText(m *tb.Message) {
 	err := utils.GatherData(m.Sender)
 	if err != nil {
 		utils.ErrorReporting(err, m)
 	}
+	err = utils.CountActivity(m)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	if checkProbation(m) || checkLinkMute(m) || checkBlacklist(m) {
+		return
+	}
+	if checkFlood(m, "messages", utils.Config.Antiflood.Messages) {
+		return
+	}
+	checkTriggers(m)
+}
+
+//Count activity and check flood on incoming sticker
+func OnSticker(m *tb.Message) {
+	err := utils.CountActivity(m)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	checkFlood(m, "stickers", utils.Config.Antiflood.Stickers)
 }
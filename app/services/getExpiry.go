@@ -0,0 +1,30 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+)
+
+//Remove expired Gets once an hour
+func GetExpiryService() {
+	for {
+		var names []string
+		result := utils.DB.Model(&utils.Get{}).Where("expires_at <= ?", time.Now()).Pluck("name", &names)
+		if result.Error != nil {
+			log.Println(result.Error.Error())
+		}
+		for _, name := range names {
+			result = utils.DB.Delete(&utils.Get{Name: name})
+			if result.Error != nil {
+				log.Println(result.Error.Error())
+				continue
+			}
+			utils.DB.Where(&utils.Trigger{GetName: name}).Delete(&utils.Trigger{})
+			utils.DB.Where(&utils.GetUsage{GetName: name}).Delete(&utils.GetUsage{})
+			log.Printf("Expired get %v removed.", name)
+		}
+		time.Sleep(time.Hour)
+	}
+}
@@ -2,34 +2,137 @@ package services
 
 import (
 	"fmt"
+	"hash/fnv"
 	"log"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/NexonSU/telegram-go-chatbot/app/utils"
 	tb "gopkg.in/tucnak/telebot.v2"
 )
 
+var inlineResults = struct {
+	sync.Mutex
+	list map[string]string
+}{list: make(map[string]string)}
+
+//Stable inline result ID of Get
+func inlineResultID(name string) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(name))
+	id := strconv.FormatUint(hash.Sum64(), 16)
+	inlineResults.Lock()
+	inlineResults.list[id] = name
+	inlineResults.Unlock()
+	return id
+}
+
+const inlinePageSize = 50
+
+//Gets, that can't be sent as inline result
+var inlineUnsupported = []string{"Album", "Poll", "VideoNote"}
+
+//Query prefix to send text gets without bold title
+const inlinePlainPrefix = "!"
+
+//LIKE pattern matching names, which contain every query rune in the same order
+func inlineLikePattern(query string) string {
+	var pattern strings.Builder
+	pattern.WriteString("%")
+	for _, r := range strings.ToLower(query) {
+		if r == '%' || r == '_' || r == '\\' {
+			pattern.WriteRune('\\')
+		}
+		pattern.WriteRune(r)
+		pattern.WriteString("%")
+	}
+	return pattern.String()
+}
+
+//Gets matching inline query, best matches first
+func inlineGets(query string, userID int) ([]utils.Get, error) {
+	var gets []utils.Get
+	request := utils.DB.Model(utils.Get{}).Select("gets.*").Scopes(utils.NotExpired, utils.OrderByPopularity).Where("gets.type NOT IN ?", inlineUnsupported)
+	if query != "" {
+		request = request.Where("gets.name LIKE ? ESCAPE '\\'", inlineLikePattern(query))
+	}
+	result := request.Find(&gets)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if query == "" {
+		return inlinePersonalOrder(gets, userID)
+	}
+	var scored []utils.Get
+	scores := make(map[string]int)
+	for _, get := range gets {
+		score, ok := utils.FuzzyScore(query, get.Name)
+		if !ok {
+			continue
+		}
+		scores[get.Name] = score
+		scored = append(scored, get)
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scores[scored[i].Name] > scores[scored[j].Name]
+	})
+	return scored, nil
+}
+
+//Move user's recent and favourite Gets to the top
+func inlinePersonalOrder(gets []utils.Get, userID int) ([]utils.Get, error) {
+	var recent []string
+	var favourite []string
+	result := utils.DB.Model(&utils.GetUsage{}).Where("user_id = ?", userID).Group("get_name").Order("MAX(date) DESC").Limit(10).Pluck("get_name", &recent)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	result = utils.DB.Model(&utils.GetUsage{}).Where("user_id = ?", userID).Group("get_name").Order("SUM(count) DESC").Limit(10).Pluck("get_name", &favourite)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	rank := make(map[string]int)
+	for i, name := range recent {
+		rank[name] = 2*len(recent) + len(favourite) - i
+	}
+	for i, name := range favourite {
+		if _, ok := rank[name]; !ok {
+			rank[name] = len(favourite) - i
+		}
+	}
+	sort.SliceStable(gets, func(i, j int) bool {
+		return rank[gets[i].Name] > rank[gets[j].Name]
+	})
+	return gets, nil
+}
+
 //Answer on inline query
 func OnInline(q *tb.Query) {
-	var count int64
-	gets := utils.DB.Limit(50).Model(utils.Get{}).Where("name LIKE ?", "%"+q.Text+"%").Count(&count)
-	get_rows, err := gets.Rows()
+	query := strings.TrimSpace(q.Text)
+	plain := utils.Config.Telegram.InlinePlainText || strings.HasPrefix(query, inlinePlainPrefix)
+	query = strings.TrimSpace(strings.TrimPrefix(query, inlinePlainPrefix))
+	gets, err := inlineGets(query, q.From.ID)
 	if err != nil {
 		log.Println(err.Error())
 		return
 	}
-	if count > 50 {
-		count = 50
+	offset, _ := strconv.Atoi(q.Offset)
+	if offset < 0 || offset > len(gets) {
+		offset = len(gets)
+	}
+	end := offset + inlinePageSize
+	var nextOffset string
+	if end < len(gets) {
+		nextOffset = strconv.Itoa(end)
+	} else {
+		end = len(gets)
 	}
-	results := make(tb.Results, count)
+	gets = gets[offset:end]
+	results := make(tb.Results, len(gets))
 	var i int
-	for get_rows.Next() {
-		var get utils.Get
-		err := utils.DB.ScanRows(get_rows, &get)
-		if err != nil {
-			log.Println(err.Error())
-			return
-		}
+	for _, get := range gets {
 		switch {
 		case get.Type == "Animation":
 			results[i] = &tb.GifResult{
@@ -67,30 +170,78 @@ func OnInline(q *tb.Query) {
 				Caption: get.Caption,
 				Cache:   get.Data,
 			}
+		case get.Type == "Sticker":
+			results[i] = &tb.StickerResult{
+				Cache: get.Data,
+			}
 		case get.Type == "Text":
 			results[i] = &tb.ArticleResult{
 				Title:       get.Name,
 				Description: get.Data,
 			}
+			text := fmt.Sprintf("<b>%v</b>\n%v", get.Name, get.Data)
+			if plain {
+				text = get.Data
+			}
 			results[i].SetContent(tb.InputMessageContent(&tb.InputTextMessageContent{
-				Text:      fmt.Sprintf("<b>%v</b>\n%v", get.Name, get.Data),
+				Text:      text,
 				ParseMode: "HTML",
 			}))
 		default:
 			log.Printf("Не удалось отправить гет %v через inline.", get.Name)
+			continue
 		}
 
-		results[i].SetResultID(strconv.Itoa(i))
+		results[i].SetResultID(inlineResultID(get.Name))
+		if keyboard := utils.GetKeyboard(get); keyboard != nil {
+			results[i].SetReplyMarkup(keyboard)
+		}
 
 		i++
 	}
 
 	err = utils.Bot.Answer(q, &tb.QueryResponse{
-		Results:   results,
-		CacheTime: 0,
+		Results:    results[:i],
+		CacheTime:  0,
+		IsPersonal: query == "",
+		NextOffset: nextOffset,
 	})
 
 	if err != nil {
 		log.Println(err.Error())
 	}
 }
+
+//Get name of inline result ID, IDs are rebuilt from DB after restart
+func inlineResultName(id string) (string, bool) {
+	inlineResults.Lock()
+	name, ok := inlineResults.list[id]
+	inlineResults.Unlock()
+	if ok {
+		return name, true
+	}
+	var names []string
+	result := utils.DB.Model(&utils.Get{}).Pluck("name", &names)
+	if result.Error != nil {
+		log.Println(result.Error.Error())
+		return "", false
+	}
+	for _, e := range names {
+		if inlineResultID(e) == id {
+			name, ok = e, true
+		}
+	}
+	return name, ok
+}
+
+//Count Get usage on chosen inline result
+func OnInlineResult(r *tb.ChosenInlineResult) {
+	name, ok := inlineResultName(r.ResultID)
+	if !ok {
+		return
+	}
+	err := utils.CountGetUsage(name, r.From.ID)
+	if err != nil {
+		log.Println(err.Error())
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+//Delete links of users, muted with /mute links, true if message was deleted
+func checkLinkMute(m *tb.Message) bool {
+	if m.Private() || m.Sender == nil || !hasLinks(m) {
+		return false
+	}
+	var restriction utils.Restriction
+	result := utils.DB.Where(&utils.Restriction{ChatID: m.Chat.ID, UserID: m.Sender.ID, Type: "links"}).Limit(1).Find(&restriction)
+	if result.RowsAffected == 0 || (restriction.UntilDate != 0 && restriction.UntilDate <= time.Now().Unix()) {
+		return false
+	}
+	err := utils.Bot.Delete(m)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return false
+	}
+	notice, err := utils.Bot.Send(m.Chat, fmt.Sprintf("%v, тебе запрещено отправлять ссылки%v.", utils.MentionUser(m.Sender), utils.RestrictionTimeMessage(restriction.UntilDate)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return true
+	}
+	time.AfterFunc(30*time.Second, func() {
+		_ = utils.Bot.Delete(notice)
+	})
+	return true
+}
+
+func hasLinks(m *tb.Message) bool {
+	for _, entities := range [][]tb.MessageEntity{m.Entities, m.CaptionEntities} {
+		for _, entity := range entities {
+			if entity.Type == tb.EntityURL || entity.Type == tb.EntityTextLink {
+				return true
+			}
+		}
+	}
+	return false
+}
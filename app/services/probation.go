@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+//Delete links, invites, channel mentions and channel reposts of newcomers on probation, true if message was deleted
+func checkProbation(m *tb.Message) bool {
+	if !utils.Config.Probation.Enabled || m.Private() || m.Sender == nil {
+		return false
+	}
+	var probation utils.Probation
+	result := utils.DB.Where(&utils.Probation{UserID: m.Sender.ID, ChatID: m.Chat.ID}).Limit(1).Find(&probation)
+	if result.RowsAffected == 0 {
+		return false
+	}
+	if !probation.Until.IsZero() && time.Now().After(probation.Until) {
+		utils.DB.Delete(&probation)
+		return false
+	}
+	if !probationViolation(m) {
+		probation.Messages++
+		if utils.Config.Probation.Messages > 0 && probation.Messages >= utils.Config.Probation.Messages {
+			result = utils.DB.Delete(&probation)
+		} else {
+			result = utils.DB.Save(&probation)
+		}
+		if result.Error != nil {
+			utils.ErrorReporting(result.Error, m)
+		}
+		return false
+	}
+	err := utils.Bot.Delete(m)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return false
+	}
+	notice, err := utils.Bot.Send(m.Chat, fmt.Sprintf("%v, извини, новичкам пока нельзя отправлять ссылки, приглашения, упоминания каналов и репосты из каналов. Ограничение снимется автоматически через некоторое время.", utils.MentionUser(m.Sender)))
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return true
+	}
+	time.AfterFunc(30*time.Second, func() {
+		_ = utils.Bot.Delete(notice)
+	})
+	return true
+}
+
+func probationViolation(m *tb.Message) bool {
+	if m.OriginalChat != nil && m.OriginalChat.Type == tb.ChatChannel {
+		return true
+	}
+	text := m.Text
+	entities := m.Entities
+	if m.Caption != "" {
+		text = m.Caption
+		entities = m.CaptionEntities
+	}
+	for _, entity := range entities {
+		switch entity.Type {
+		case tb.EntityURL, tb.EntityTextLink:
+			link := entity.URL
+			if link == "" {
+				link = utils.EntityText(text, entity)
+			}
+			if !probationAllowed(link) {
+				return true
+			}
+		case tb.EntityMention:
+			if probationChannel(utils.EntityText(text, entity)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var mentionChannels = struct {
+	sync.Mutex
+	list map[string]mentionChannel
+}{list: make(map[string]mentionChannel)}
+
+type mentionChannel struct {
+	Channel bool
+	Checked time.Time
+}
+
+//Check if mention is a channel, results are cached for a day to not call getChat on every message
+func probationChannel(mention string) bool {
+	mention = strings.ToLower(mention)
+	mentionChannels.Lock()
+	cached, ok := mentionChannels.list[mention]
+	mentionChannels.Unlock()
+	if ok && time.Since(cached.Checked) < 24*time.Hour {
+		return cached.Channel
+	}
+	chat, err := utils.Bot.ChatByID(mention)
+	//Don't remember temporary errors
+	if err != nil && !strings.Contains(err.Error(), "chat not found") {
+		return false
+	}
+	channel := err == nil && chat.Type == tb.ChatChannel
+	mentionChannels.Lock()
+	for name, e := range mentionChannels.list {
+		if time.Since(e.Checked) >= 24*time.Hour {
+			delete(mentionChannels.list, name)
+		}
+	}
+	mentionChannels.list[mention] = mentionChannel{Channel: channel, Checked: time.Now()}
+	mentionChannels.Unlock()
+	return channel
+}
+
+//Check link domain against probation allowlist, subdomains are allowed too
+func probationAllowed(link string) bool {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	for _, domain := range utils.Config.Probation.Allowlist {
+		domain = strings.ToLower(domain)
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
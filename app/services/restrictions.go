@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"time"
+
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+//Notify about lapsed restrictions every minute and drop stale ones once an hour
+func RestrictionExpiryService() {
+	lastCleanup := time.Now()
+	for {
+		err := restrictionExpiry()
+		if err != nil {
+			log.Println(err.Error())
+		}
+		if time.Since(lastCleanup) > time.Hour {
+			err = restrictionCleanup()
+			if err != nil {
+				log.Println(err.Error())
+			}
+			lastCleanup = time.Now()
+		}
+		time.Sleep(time.Minute)
+	}
+}
+
+func restrictionExpiry() error {
+	var restrictions []utils.Restriction
+	result := utils.DB.Where("until_date != 0 AND until_date <= ?", time.Now().Unix()).Find(&restrictions)
+	if result.Error != nil {
+		return result.Error
+	}
+	for _, restriction := range restrictions {
+		var user tb.User
+		utils.DB.Where(&tb.User{ID: restriction.UserID}).Limit(1).Find(&user)
+		user.ID = restriction.UserID
+		switch restriction.Type {
+		case "mute":
+			_, err := utils.Bot.Send(tb.ChatID(restriction.ChatID), fmt.Sprintf("%v снова может писать в чат.", utils.MentionUser(&user)))
+			if err != nil {
+				log.Println(err.Error())
+			}
+		case "media", "stickers", "links", "polls":
+			_, err := utils.Bot.Send(tb.ChatID(restriction.ChatID), fmt.Sprintf("%v снова может отправлять %v.", utils.MentionUser(&user), utils.MuteModes[restriction.Type]))
+			if err != nil {
+				log.Println(err.Error())
+			}
+		case "ban":
+			chat, err := utils.Bot.ChatByID(fmt.Sprint(restriction.ChatID))
+			if err != nil {
+				log.Println(err.Error())
+				break
+			}
+			//User can receive message only if they started bot before
+			_, _ = utils.Bot.Send(&user, fmt.Sprintf("Срок бана в чате %v истёк, можно возвращаться.", html.EscapeString(chat.Title)))
+		}
+		result = utils.DB.Delete(&restriction)
+		if result.Error != nil {
+			return result.Error
+		}
+		//Telegram lifts whole restriction at once, saved rights aren't needed anymore
+		if restriction.Type != "ban" {
+			err := utils.ForgetRights(&tb.Chat{ID: restriction.ChatID}, &user)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//Remove restrictions, which Telegram doesn't report anymore
+func restrictionCleanup() error {
+	var restrictions []utils.Restriction
+	result := utils.DB.Find(&restrictions)
+	if result.Error != nil {
+		return result.Error
+	}
+	for _, restriction := range restrictions {
+		member, err := utils.Bot.ChatMemberOf(&tb.Chat{ID: restriction.ChatID}, &tb.User{ID: restriction.UserID})
+		if err != nil {
+			log.Println(err.Error())
+			continue
+		}
+		stale := false
+		switch restriction.Type {
+		case "mute":
+			stale = member.Role != tb.Restricted || member.CanSendMessages
+		case "media":
+			stale = member.Role != tb.Restricted || member.CanSendMedia
+		case "stickers":
+			stale = member.Role != tb.Restricted || member.CanSendOther
+		case "links":
+			stale = member.Role != tb.Restricted || member.CanAddPreviews
+		case "polls":
+			stale = member.Role != tb.Restricted || member.CanSendPolls
+		case "ban":
+			stale = member.Role != tb.Kicked
+		}
+		if !stale {
+			continue
+		}
+		result = utils.DB.Delete(&restriction)
+		if result.Error != nil {
+			return result.Error
+		}
+		if restriction.Type != "ban" {
+			err = utils.ForgetRights(&tb.Chat{ID: restriction.ChatID}, &tb.User{ID: restriction.UserID})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
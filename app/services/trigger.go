@@ -0,0 +1,79 @@
+package services
+
+import (
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"gorm.io/gorm"
+)
+
+var triggerRegexps = struct {
+	sync.Mutex
+	list map[string]*regexp.Regexp
+}{list: make(map[string]*regexp.Regexp)}
+
+func triggerRegexp(trigger utils.Trigger) (*regexp.Regexp, error) {
+	pattern := trigger.Pattern
+	if trigger.Literal {
+		//Literal triggers match only whole words
+		pattern = `(^|[^\p{L}\p{N}])` + regexp.QuoteMeta(pattern) + `($|[^\p{L}\p{N}])`
+	}
+	triggerRegexps.Lock()
+	defer triggerRegexps.Unlock()
+	if re, ok := triggerRegexps.list[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, err
+	}
+	triggerRegexps.list[pattern] = re
+	return re, nil
+}
+
+//Reply with Get if message matches keyword trigger
+func checkTriggers(m *tb.Message) {
+	if m.Text == "" || m.Text[0] == '/' {
+		return
+	}
+	var triggers []utils.Trigger
+	result := utils.DB.Where(&utils.Trigger{ChatID: m.Chat.ID}).Find(&triggers)
+	if result.Error != nil {
+		utils.ErrorReporting(result.Error, m)
+		return
+	}
+	for _, trigger := range triggers {
+		re, err := triggerRegexp(trigger)
+		if err != nil || !re.MatchString(m.Text) {
+			continue
+		}
+		if time.Since(trigger.LastHit) < time.Duration(trigger.Cooldown)*time.Second {
+			continue
+		}
+		if utils.RandInt(0, 100) >= trigger.Probability {
+			continue
+		}
+		var get utils.Get
+		result := utils.DB.Scopes(utils.NotExpired).Where(&utils.Get{Name: trigger.GetName}).First(&get)
+		if result.RowsAffected == 0 {
+			continue
+		}
+		//Claim hit, so simultaneous messages don't fire trigger twice
+		result = utils.DB.Model(&utils.Trigger{}).Where("id = ? AND last_hit < ?", trigger.ID, time.Now().Add(-time.Duration(trigger.Cooldown)*time.Second)).Updates(map[string]interface{}{"hits": gorm.Expr("hits + 1"), "last_hit": time.Now()})
+		if result.Error != nil {
+			utils.ErrorReporting(result.Error, m)
+			return
+		}
+		if result.RowsAffected != 1 {
+			continue
+		}
+		err = utils.SendGet(m.Chat, get, &tb.SendOptions{ReplyTo: m})
+		if err != nil {
+			utils.ErrorReporting(err, m)
+		}
+		return
+	}
+}
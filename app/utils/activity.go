@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"time"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//Count message of sender in chat
+func CountActivity(m *tb.Message) error {
+	if m.Private() || m.Sender == nil {
+		return nil
+	}
+	result := DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_id"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"messages": gorm.Expr("messages + 1")}),
+	}).Create(&ChatActivity{ChatID: m.Chat.ID, UserID: m.Sender.ID, FirstMessage: time.Now(), Messages: 1})
+	return result.Error
+}
+
+//User wrote the first message in chat long enough ago or wrote enough messages to vote
+func VoteEligible(chat *tb.Chat, user *tb.User) bool {
+	if Config.Votes.MinDays < 0 && Config.Votes.MinMessages < 0 {
+		return true
+	}
+	var activity ChatActivity
+	result := DB.Where(&ChatActivity{ChatID: chat.ID, UserID: user.ID}).Limit(1).Find(&activity)
+	if result.RowsAffected == 0 {
+		return false
+	}
+	if Config.Votes.MinDays > 0 && time.Since(activity.FirstMessage) >= time.Duration(Config.Votes.MinDays)*24*time.Hour {
+		return true
+	}
+	return Config.Votes.MinMessages > 0 && activity.Messages >= Config.Votes.MinMessages
+}
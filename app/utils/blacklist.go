@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//Latin letters and digits, that look like Cyrillic letters
+var homoglyphs = map[rune]rune{
+	'a': 'а',
+	'b': 'в',
+	'c': 'с',
+	'e': 'е',
+	'h': 'н',
+	'k': 'к',
+	'm': 'м',
+	'o': 'о',
+	'p': 'р',
+	't': 'т',
+	'x': 'х',
+	'y': 'у',
+	'ё': 'е',
+	'0': 'о',
+	'3': 'з',
+	'6': 'б',
+}
+
+//Lowercase text, replace homoglyphs and collapse repeated characters
+func NormalizeText(text string) string {
+	var normalized []rune
+	for _, r := range strings.ToLower(text) {
+		if replacement, ok := homoglyphs[r]; ok {
+			r = replacement
+		}
+		if len(normalized) != 0 && normalized[len(normalized)-1] == r {
+			continue
+		}
+		normalized = append(normalized, r)
+	}
+	return string(normalized)
+}
+
+var blacklistRegexps = struct {
+	sync.Mutex
+	list map[string]*regexp.Regexp
+}{list: make(map[string]*regexp.Regexp)}
+
+//Compile blacklist regex once
+func BlacklistRegexp(pattern string) (*regexp.Regexp, error) {
+	blacklistRegexps.Lock()
+	defer blacklistRegexps.Unlock()
+	if re, ok := blacklistRegexps.list[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, err
+	}
+	blacklistRegexps.list[pattern] = re
+	return re, nil
+}
+
+//First blacklist entry of chat matching text, nil if there is none
+func BlacklistMatch(chatID int64, text string) (*Blacklist, error) {
+	var entries []Blacklist
+	result := DB.Where(&Blacklist{ChatID: chatID}).Find(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	normalized := NormalizeText(text)
+	for i, entry := range entries {
+		if !entry.Regex {
+			//Word should match whole, not as a part of another word
+			re, err := BlacklistRegexp(`(^|[^\p{L}\p{N}])` + regexp.QuoteMeta(NormalizeText(entry.Pattern)) + `($|[^\p{L}\p{N}])`)
+			if err == nil && re.MatchString(normalized) {
+				return &entries[i], nil
+			}
+			continue
+		}
+		re, err := BlacklistRegexp(entry.Pattern)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(text) || re.MatchString(normalized) {
+			return &entries[i], nil
+		}
+	}
+	return nil, nil
+}
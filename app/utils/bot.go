@@ -38,6 +38,13 @@ func BotInit() tb.Bot {
 			AllowedUpdates: Config.Telegram.AllowedUpdates,
 		}
 	}
+	//Remember every group message for /purge
+	settings.Poller = tb.NewMiddlewarePoller(settings.Poller, func(update *tb.Update) bool {
+		if update.Message != nil {
+			RememberMessage(update.Message)
+		}
+		return true
+	})
 	var Bot, err = tb.NewBot(settings)
 	if err != nil {
 		log.Println(Config.Telegram.BotApiUrl)
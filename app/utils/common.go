@@ -87,38 +87,92 @@ func ErrorReporting(err error, message *tb.Message) {
 	}
 }
 
-func FindUserInMessage(m tb.Message) (tb.User, int64, error) {
+//Find target user, restriction end date and reason in command message, ErrInvalidDuration if duration is mistyped
+func FindUserInMessage(m tb.Message) (tb.User, int64, string, error) {
+	return FindUserAfterArgs(m, 0)
+}
+
+//Find target user like FindUserInMessage, skipping first command args, which aren't about user
+func FindUserAfterArgs(m tb.Message, skip int) (tb.User, int64, string, error) {
+	var untildate = time.Now().Unix()
+	user, args, err := FindUserWithArgs(m, skip)
+	if err != nil {
+		return user, untildate, "", err
+	}
+	if len(args) != 0 {
+		duration, err := ParseDuration(args[0])
+		switch {
+		case err == nil:
+			untildate += int64(duration.Seconds())
+			args = args[1:]
+		//Mistyped duration like 2hrs or 1.5h shouldn't become part of reason and turn into forever
+		case args[0][0] >= '0' && args[0][0] <= '9':
+			return user, untildate, "", ErrInvalidDuration
+		}
+	}
+	return user, untildate, strings.Join(args, " "), nil
+}
+
+//Find target user in command message and get command args after user
+func FindUserWithArgs(m tb.Message, skip int) (tb.User, []string, error) {
 	var user tb.User
 	var err error = nil
-	var untildate = time.Now().Unix()
-	var text = strings.Split(m.Text, " ")
+	var text = strings.Fields(m.Text)
+	if len(text) < skip+1 {
+		return user, nil, errors.New("не хватает аргументов")
+	}
 	if m.ReplyTo != nil {
-		user = *m.ReplyTo.Sender
-		if len(text) == 2 {
-			addtime, err := strconv.ParseInt(text[1], 10, 64)
-			if err != nil {
-				return user, untildate, err
-			}
-			untildate += addtime
-		}
-	} else {
-		if len(text) == 1 {
-			err = errors.New("пользователь не найден")
-			return user, untildate, err
-		}
-		user, err = GetUserFromDB(text[1])
+		user, err = replyTarget(m.ReplyTo)
+		return user, text[1+skip:], err
+	}
+	if mention := textMention(&m); mention != nil {
+		return *mention.User, strings.Fields(TextAfterEntity(m.Text, *mention)), nil
+	}
+	if len(text) == 1+skip {
+		return user, nil, errors.New("пользователь не указан")
+	}
+	user, err = GetUserFromDB(text[1+skip])
+	var ambiguous *AmbiguousUserError
+	if errors.As(err, &ambiguous) {
+		err = askUserChoice(&m, ambiguous.Users, 1+skip)
 		if err != nil {
-			return user, untildate, err
+			return user, nil, err
 		}
-		if len(text) == 3 {
-			addtime, err := strconv.ParseInt(text[2], 10, 64)
-			if err != nil {
-				return user, untildate, err
-			}
-			untildate += addtime
+		return user, nil, ErrUserChoice
+	}
+	if err != nil {
+		return user, nil, err
+	}
+	return user, text[2+skip:], nil
+}
+
+//Author of replied message, original author if staff or bot forwarded it
+func replyTarget(m *tb.Message) (tb.User, error) {
+	if m.OriginalUnixtime == 0 || (m.Sender.ID != Bot.Me.ID && !IsAdminOrModer(m.Sender.Username)) {
+		return *m.Sender, nil
+	}
+	if m.OriginalSender == nil {
+		return tb.User{}, errors.New("автор пересланного сообщения скрыл свой аккаунт")
+	}
+	return *m.OriginalSender, nil
+}
+
+//First mention of user without username in command message
+func textMention(m *tb.Message) *tb.MessageEntity {
+	for i, entity := range m.Entities {
+		if entity.Type == tb.EntityTMention && entity.User != nil {
+			return &m.Entities[i]
 		}
 	}
-	return user, untildate, err
+	return nil
+}
+
+//Reason line for moderation messages, empty if there is no reason
+func ReasonMessage(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return fmt.Sprintf("\nПричина: %v", html.EscapeString(reason))
 }
 
 func GatherData(user *tb.User) error {
@@ -131,17 +185,43 @@ func GatherData(user *tb.User) error {
 	return nil
 }
 
+//Find user by @username, ID or part of name
 func GetUserFromDB(findstring string) (tb.User, error) {
 	var user tb.User
-	var err error = nil
-	if string(findstring[0]) == "@" {
-		user.Username = findstring[1:]
-	} else {
-		user.ID, err = strconv.Atoi(findstring)
+	findstring = strings.TrimSpace(findstring)
+	if findstring == "" {
+		return user, errors.New("пользователь не указан")
 	}
-	result := DB.Where(&user).First(&user)
-	if result.Error != nil {
-		err = result.Error
+	if strings.HasPrefix(findstring, "@") {
+		result := DB.Where("LOWER(username) = LOWER(?)", findstring[1:]).Limit(1).Find(&user)
+		if result.Error != nil {
+			return user, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return user, fmt.Errorf("пользователь %v не найден в базе бота", findstring)
+		}
+		return user, nil
+	}
+	if id, err := strconv.Atoi(findstring); err == nil {
+		result := DB.Where(&tb.User{ID: id}).Limit(1).Find(&user)
+		if result.Error != nil {
+			return user, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return user, fmt.Errorf("пользователь с ID %v не найден в базе бота", id)
+		}
+		return user, nil
+	}
+	users, exact, err := FindUsersByName(findstring)
+	if err != nil {
+		return user, err
+	}
+	switch {
+	case len(users) == 0:
+		return user, fmt.Errorf("пользователь «%v» не найден", findstring)
+	//Partial match can be somebody else, so it always should be confirmed
+	case len(users) == 1 && exact:
+		return users[0], nil
 	}
-	return user, err
+	return user, &AmbiguousUserError{Query: findstring, Users: users}
 }
@@ -7,6 +7,12 @@ import (
 	"os"
 )
 
+type WarnStep struct {
+	Warns    int    `json:"warns"`
+	Action   string `json:"action"` //mute, kick or ban
+	Duration string `json:"duration"`
+}
+
 type Configuration struct {
 	Telegram struct {
 		//your token
@@ -17,8 +23,70 @@ type Configuration struct {
 		Admins         []string `json:"admins"`
 		Moders         []string `json:"moders"`
 		SysAdmin       int      `json:"sysadmin"`
-		AllowedUpdates []string `json:"allowed_updates"`
+		AllowedUpdates []string `json:"allowed_updates"` //chosen_inline_result also needs inline feedback enabled in @BotFather
+		//send text gets via inline without bold title, also can be asked with ! before inline query
+		InlinePlainText bool `json:"inline_plain_text"`
+		//channel for moderation log, username or ID
+		LogChannel string `json:"log_channel"`
+		//seconds, while moderation action can be undone
+		UndoWindow int `json:"undo_window"`
 	}
+	Warns struct {
+		//days until warn expires
+		ExpireDays int        `json:"expire_days"`
+		Ladder     []WarnStep `json:"ladder"`
+	} `json:"warns"`
+	Reports struct {
+		//chat for reports, username or ID, moderators get reports in private messages if empty
+		StaffChat string `json:"staff_chat"`
+		//seconds between reports from one user
+		Cooldown int `json:"cooldown"`
+	} `json:"reports"`
+	Appeals struct {
+		//seconds after denied appeal until user can appeal again
+		Cooldown int `json:"cooldown"`
+	} `json:"appeals"`
+	Antiflood struct {
+		Enabled bool `json:"enabled"`
+		//sliding window in seconds
+		Window   int `json:"window"`
+		Messages int `json:"messages"`
+		Stickers int `json:"stickers"`
+		Media    int `json:"media"`
+		//first mute duration, doubles on every repeat in 24 hours
+		MuteDuration   string   `json:"mute_duration"`
+		DeleteMessages bool     `json:"delete_messages"`
+		Trusted        []string `json:"trusted"`
+	} `json:"antiflood"`
+	Probation struct {
+		Enabled bool `json:"enabled"`
+		//newcomer probation ends after hours or messages, negative value disables the limit
+		Hours     int      `json:"hours"`
+		Messages  int      `json:"messages"`
+		Allowlist []string `json:"allowlist"`
+	} `json:"probation"`
+	Antiraid struct {
+		Enabled bool `json:"enabled"`
+		//raid mode starts after joins in window seconds
+		Joins  int `json:"joins"`
+		Window int `json:"window"`
+		//seconds without joins until raid mode ends
+		Quiet int `json:"quiet"`
+		//ban or restrict joiners during raid
+		Action string `json:"action"`
+	} `json:"antiraid"`
+	Votes struct {
+		Enabled bool `json:"enabled"`
+		//distinct eligible voters needed in window seconds
+		Voters int `json:"voters"`
+		Window int `json:"window"`
+		//voter is eligible after days since the first message seen by bot or messages in chat, negative value disables the limit
+		MinDays      int    `json:"min_days"`
+		MinMessages  int    `json:"min_messages"`
+		MuteDuration string `json:"mute_duration"`
+		//seconds between votes started by one user or against one user
+		Cooldown int `json:"cooldown"`
+	} `json:"votes"`
 	Webhook struct {
 		Listen            string `json:"listen"`
 		EndpointPublicURL string `json:"endpoint_public_url"`
@@ -45,11 +113,115 @@ func ConfigInit(file string) Configuration {
 		if err != nil {
 			log.Fatal(err)
 		}
+		//empty list means all updates except chat_member, so add get usage stats and edits checks only to explicit list
+		if len(Config.Telegram.AllowedUpdates) != 0 {
+			for _, required := range []string{"chosen_inline_result", "edited_message"} {
+				found := false
+				for _, update := range Config.Telegram.AllowedUpdates {
+					if update == required {
+						found = true
+					}
+				}
+				if !found {
+					Config.Telegram.AllowedUpdates = append(Config.Telegram.AllowedUpdates, required)
+				}
+			}
+		}
+		if Config.Telegram.UndoWindow == 0 {
+			Config.Telegram.UndoWindow = 300
+		}
+		if Config.Warns.ExpireDays == 0 {
+			Config.Warns.ExpireDays = 14
+		}
+		if Config.Warns.Ladder == nil {
+			Config.Warns.Ladder = []WarnStep{{Warns: 3, Action: "ban", Duration: "1w"}}
+		}
+		if Config.Reports.Cooldown == 0 {
+			Config.Reports.Cooldown = 300
+		}
+		if Config.Appeals.Cooldown == 0 {
+			Config.Appeals.Cooldown = 86400
+		}
+		if Config.Antiflood.Window == 0 {
+			Config.Antiflood.Window = 10
+		}
+		if Config.Antiflood.Messages == 0 {
+			Config.Antiflood.Messages = 7
+		}
+		if Config.Antiflood.Stickers == 0 {
+			Config.Antiflood.Stickers = 3
+		}
+		if Config.Antiflood.Media == 0 {
+			Config.Antiflood.Media = 5
+		}
+		if Config.Antiflood.MuteDuration == "" {
+			Config.Antiflood.MuteDuration = "10m"
+		}
+		if Config.Probation.Hours == 0 {
+			Config.Probation.Hours = 24
+		}
+		if Config.Probation.Messages == 0 {
+			Config.Probation.Messages = 10
+		}
+		if Config.Antiraid.Joins == 0 {
+			Config.Antiraid.Joins = 10
+		}
+		if Config.Antiraid.Window == 0 {
+			Config.Antiraid.Window = 60
+		}
+		if Config.Antiraid.Quiet == 0 {
+			Config.Antiraid.Quiet = 600
+		}
+		if Config.Antiraid.Action == "" {
+			Config.Antiraid.Action = "ban"
+		}
+		if Config.Votes.Voters == 0 {
+			Config.Votes.Voters = 5
+		}
+		if Config.Votes.Window == 0 {
+			Config.Votes.Window = 600
+		}
+		if Config.Votes.MinDays == 0 {
+			Config.Votes.MinDays = 7
+		}
+		if Config.Votes.MinMessages == 0 {
+			Config.Votes.MinMessages = 50
+		}
+		if Config.Votes.MuteDuration == "" {
+			Config.Votes.MuteDuration = "1h"
+		}
+		if Config.Votes.Cooldown == 0 {
+			Config.Votes.Cooldown = 1800
+		}
 	} else if os.IsNotExist(err) {
 		Config.Telegram.Admins = []string{}
 		Config.Telegram.Moders = []string{}
 		Config.Telegram.BotApiUrl = "https://api.telegram.org"
-		Config.Telegram.AllowedUpdates = []string{"message", "channel_post", "callback_query", "chat_member"}
+		Config.Telegram.UndoWindow = 300
+		Config.Warns.ExpireDays = 14
+		Config.Warns.Ladder = []WarnStep{{Warns: 3, Action: "ban", Duration: "1w"}}
+		Config.Reports.Cooldown = 300
+		Config.Appeals.Cooldown = 86400
+		Config.Antiflood.Window = 10
+		Config.Antiflood.Messages = 7
+		Config.Antiflood.Stickers = 3
+		Config.Antiflood.Media = 5
+		Config.Antiflood.MuteDuration = "10m"
+		Config.Antiflood.Trusted = []string{}
+		Config.Probation.Hours = 24
+		Config.Probation.Messages = 10
+		Config.Probation.Allowlist = []string{"youtube.com", "youtu.be"}
+		Config.Antiraid.Joins = 10
+		Config.Antiraid.Window = 60
+		Config.Antiraid.Quiet = 600
+		Config.Antiraid.Action = "ban"
+		Config.Votes.Voters = 5
+		Config.Votes.Window = 600
+		Config.Votes.MinDays = 7
+		Config.Votes.MinMessages = 50
+		Config.Votes.MuteDuration = "1h"
+		Config.Votes.Cooldown = 1800
+		Config.Telegram.AllowedUpdates = []string{"message", "edited_message", "channel_post", "callback_query", "chat_member", "inline_query", "chosen_inline_result"}
 		jsonData, _ := json.MarshalIndent(Config, "", "\t")
 		_ = ioutil.WriteFile(file, jsonData, 0600)
 		log.Fatal(err)
@@ -10,10 +10,20 @@ import (
 )
 
 type Get struct {
-	Name    string `gorm:"primaryKey"`
-	Type    string
-	Data    string
-	Caption string
+	Name      string `gorm:"primaryKey"`
+	Type      string
+	Data      string
+	Caption   string
+	Category  string
+	Buttons   string
+	ExpiresAt *time.Time
+}
+
+type GetUsage struct {
+	GetName string    `gorm:"primaryKey"`
+	UserID  int       `gorm:"primaryKey"`
+	Date    time.Time `gorm:"primaryKey"`
+	Count   int
 }
 
 type PidorStats struct {
@@ -29,10 +39,141 @@ type Duelist struct {
 	Kills  int
 }
 
-type Warn struct {
-	UserID   int `gorm:"primaryKey"`
-	Amount   int
-	LastWarn time.Time
+type Warning struct {
+	ID        int `gorm:"primaryKey"`
+	UserID    int `gorm:"index"`
+	IssuerID  int
+	Reason    string
+	Date      time.Time
+	ExpiresAt time.Time
+	//set by /unwarn, revoked warning stays in history
+	RevokerID int
+	RevokedAt *time.Time
+}
+
+type Trigger struct {
+	ID          int `gorm:"primaryKey"`
+	Pattern     string
+	Literal     bool
+	GetName     string
+	ChatID      int64
+	Cooldown    int
+	Probability int
+	Hits        int
+	LastHit     time.Time
+}
+
+type ModerationAction struct {
+	ID        int `gorm:"primaryKey"`
+	Date      time.Time
+	ChatID    int64
+	MessageID int
+	ActorID   int
+	TargetID  int `gorm:"index"`
+	Action    string
+	Duration  int64
+	Reason    string
+}
+
+type Appeal struct {
+	ID        int `gorm:"primaryKey"`
+	Date      time.Time
+	UserID    int `gorm:"index"`
+	ChatID    int64
+	Text      string
+	HandlerID int
+	Decision  string
+	HandledAt time.Time
+}
+
+type Note struct {
+	ID       int `gorm:"primaryKey"`
+	UserID   int `gorm:"index"`
+	AuthorID int
+	Text     string
+	Date     time.Time
+}
+
+type Report struct {
+	ID         int `gorm:"primaryKey"`
+	Date       time.Time
+	ChatID     int64
+	MessageID  int
+	ReporterID int `gorm:"index"`
+	TargetID   int
+	Reason     string
+	HandlerID  int
+	Action     string
+	HandledAt  time.Time
+}
+
+//Messages of user in chat, counted since the first message seen by bot, not since user joined
+type ChatActivity struct {
+	ChatID       int64     `gorm:"primaryKey"`
+	UserID       int       `gorm:"primaryKey"`
+	FirstMessage time.Time `gorm:"column:first_seen"`
+	Messages     int
+}
+
+type Probation struct {
+	UserID   int   `gorm:"primaryKey"`
+	ChatID   int64 `gorm:"primaryKey"`
+	Until    time.Time
+	Messages int
+}
+
+type Blacklist struct {
+	ID       int `gorm:"primaryKey"`
+	ChatID   int64
+	Pattern  string
+	Regex    bool
+	Action   string
+	Duration string
+	Hits     int
+}
+
+type FederationChat struct {
+	ChatID int64 `gorm:"primaryKey"`
+	Title  string
+	OptOut bool
+}
+
+type FederationBan struct {
+	UserID    int       `gorm:"primaryKey" json:"user_id"`
+	ActorID   int       `json:"actor_id"`
+	Reason    string    `json:"reason,omitempty"`
+	Date      time.Time `json:"date"`
+	UntilDate int64     `json:"until_date,omitempty"`
+}
+
+type Restriction struct {
+	ChatID    int64  `gorm:"primaryKey"`
+	UserID    int    `gorm:"primaryKey"`
+	Type      string `gorm:"primaryKey"`
+	UntilDate int64
+	ActorID   int
+	Reason    string
+	Date      time.Time
+}
+
+//Send rights of chat member before the first mute, restored on unmute
+type SavedRights struct {
+	ChatID          int64 `gorm:"primaryKey"`
+	UserID          int   `gorm:"primaryKey"`
+	Restricted      bool
+	RestrictedUntil int64
+	CanSendMessages bool
+	CanSendMedia    bool
+	CanSendPolls    bool
+	CanSendOther    bool
+	CanAddPreviews  bool
+}
+
+//Active raid mode, chat permissions before raid are kept in JSON to restore them after restart
+type RaidState struct {
+	ChatID      int64 `gorm:"primaryKey"`
+	Started     time.Time
+	Permissions string
 }
 
 type ZavtraStream struct {
@@ -53,10 +194,37 @@ func DataBaseInit(file string) gorm.DB {
 	}
 
 	//Create tables, if they not exists in DB
-	err = database.AutoMigrate(tb.User{}, Get{}, Warn{}, PidorStats{}, PidorList{}, Duelist{}, ZavtraStream{})
+	err = database.AutoMigrate(tb.User{}, Get{}, GetUsage{}, Warning{}, PidorStats{}, PidorList{}, Duelist{}, Trigger{}, ModerationAction{}, Appeal{}, Note{}, Report{}, ChatActivity{}, Probation{}, Blacklist{}, FederationChat{}, FederationBan{}, Restriction{}, SavedRights{}, RaidState{}, ZavtraStream{})
 	if err != nil {
 		log.Println(err)
 	}
+
+	//Move warn counters from old warns table to warnings history, old table is dropped only if all of them are moved
+	if database.Migrator().HasTable("warns") {
+		err = database.Transaction(func(tx *gorm.DB) error {
+			var warns []struct {
+				UserID   int
+				Amount   int
+				LastWarn time.Time
+			}
+			result := tx.Table("warns").Find(&warns)
+			if result.Error != nil {
+				return result.Error
+			}
+			for _, warn := range warns {
+				for i := 0; i < warn.Amount; i++ {
+					result = tx.Create(&Warning{UserID: warn.UserID, Date: warn.LastWarn, ExpiresAt: warn.LastWarn.AddDate(0, 0, Config.Warns.ExpireDays)})
+					if result.Error != nil {
+						return result.Error
+					}
+				}
+			}
+			return tx.Migrator().DropTable("warns")
+		})
+		if err != nil {
+			log.Println(err)
+		}
+	}
 	return *database
 }
 
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDuration = errors.New("неверный формат времени")
+
+//Telegram treats restrictions shorter than 30 seconds or longer than 366 days as forever
+const (
+	MinDuration = 30 * time.Second
+	MaxDuration = 366 * 24 * time.Hour
+)
+
+var durationUnits = map[rune]time.Duration{
+	's': time.Second,
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+	'с': time.Second,
+	'м': time.Minute,
+	'ч': time.Hour,
+	'д': 24 * time.Hour,
+	'н': 7 * 24 * time.Hour,
+}
+
+//Parse human-friendly duration like 30m, 2h, 1d12h, 1w or forever, plain number is seconds, zero means forever
+func ParseDuration(text string) (time.Duration, error) {
+	duration, err := parseDuration(text)
+	if err != nil || duration == 0 {
+		return duration, err
+	}
+	if duration < MinDuration || duration > MaxDuration {
+		return 0, ErrInvalidDuration
+	}
+	return duration, nil
+}
+
+func parseDuration(text string) (time.Duration, error) {
+	text = strings.ToLower(text)
+	switch text {
+	case "forever", "навсегда":
+		return 0, nil
+	case "":
+		return 0, ErrInvalidDuration
+	}
+	if seconds, err := strconv.ParseInt(text, 10, 64); err == nil {
+		if seconds < 0 || seconds > math.MaxInt64/int64(time.Second) {
+			return 0, ErrInvalidDuration
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	var duration time.Duration
+	var number int64
+	var digits bool
+	for _, r := range text {
+		if r >= '0' && r <= '9' {
+			if number > (math.MaxInt64-int64(r-'0'))/10 {
+				return 0, ErrInvalidDuration
+			}
+			number = number*10 + int64(r-'0')
+			digits = true
+			continue
+		}
+		unit, ok := durationUnits[r]
+		if !ok || !digits || number > int64((math.MaxInt64-duration)/unit) {
+			return 0, ErrInvalidDuration
+		}
+		duration += time.Duration(number) * unit
+		number = 0
+		digits = false
+	}
+	if digits {
+		return 0, ErrInvalidDuration
+	}
+	return duration, nil
+}
+
+//Format duration in seconds like 1н 2д 3ч 4м 5с
+func FormatDuration(seconds int64) string {
+	units := []struct {
+		Name    string
+		Seconds int64
+	}{{"н", 604800}, {"д", 86400}, {"ч", 3600}, {"м", 60}, {"с", 1}}
+	var parts []string
+	for _, unit := range units {
+		if seconds >= unit.Seconds {
+			parts = append(parts, fmt.Sprintf("%v%v", seconds/unit.Seconds, unit.Name))
+			seconds %= unit.Seconds
+		}
+	}
+	if len(parts) == 0 {
+		return "0с"
+	}
+	return strings.Join(parts, " ")
+}
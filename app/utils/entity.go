@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"unicode/utf16"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+//Text of message entity, entity offsets are in UTF-16 code units
+func EntityText(text string, entity tb.MessageEntity) string {
+	encoded := utf16.Encode([]rune(text))
+	if entity.Offset < 0 || entity.Length < 0 || entity.Offset+entity.Length > len(encoded) {
+		return ""
+	}
+	return string(utf16.Decode(encoded[entity.Offset : entity.Offset+entity.Length]))
+}
+
+//Text of message after entity
+func TextAfterEntity(text string, entity tb.MessageEntity) string {
+	encoded := utf16.Encode([]rune(text))
+	if entity.Offset < 0 || entity.Length < 0 || entity.Offset+entity.Length > len(encoded) {
+		return ""
+	}
+	return string(utf16.Decode(encoded[entity.Offset+entity.Length:]))
+}
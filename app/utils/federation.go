@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+//Chat is in federation and didn't opt out
+func InFederation(chatID int64) bool {
+	var chat FederationChat
+	result := DB.Where("chat_id = ? AND opt_out = ?", chatID, false).Limit(1).Find(&chat)
+	return result.RowsAffected != 0
+}
+
+//Apply ban, unban or mute to user in every federation chat, that didn't opt out
+func FederationApply(target *tb.User, actor *tb.User, action string, untildate int64, reason string) (int, []string, error) {
+	var chats []FederationChat
+	result := DB.Where("opt_out = ?", false).Find(&chats)
+	if result.Error != nil {
+		return 0, nil, result.Error
+	}
+	var done int
+	var failed []string
+	for _, federationChat := range chats {
+		chat, err := Bot.ChatByID(strconv.FormatInt(federationChat.ChatID, 10))
+		if err == nil {
+			switch action {
+			case "ban":
+				err = Bot.Ban(chat, &tb.ChatMember{User: target, RestrictedUntil: untildate})
+				if err == nil {
+					err = TrackRestriction(chat, target, actor, "ban", untildate, reason)
+				}
+			case "unban":
+				err = Bot.Unban(chat, target)
+				if err == nil {
+					err = ForgetRestriction(chat, target, "ban")
+				}
+			case "mute":
+				err = Bot.Restrict(chat, &tb.ChatMember{User: target, RestrictedUntil: untildate})
+				if err == nil {
+					err = TrackRestriction(chat, target, actor, "mute", untildate, reason)
+				}
+			}
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%v: %v", federationChat.Title, err.Error()))
+			continue
+		}
+		done++
+	}
+	return done, failed, nil
+}
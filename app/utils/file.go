@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+//Open file by FileID through Bot API getFile, local Bot API server returns absolute path on its disk
+func OpenFile(fileID string) (io.ReadCloser, string, error) {
+	file, err := Bot.FileByID(fileID)
+	if err != nil {
+		return nil, "", err
+	}
+	if filepath.IsAbs(file.FilePath) {
+		reader, err := os.Open(file.FilePath)
+		if err == nil {
+			return reader, file.FilePath, nil
+		}
+	}
+	httpClient := &http.Client{Timeout: 5 * time.Minute}
+	httpResponse, err := httpClient.Get(fmt.Sprintf("%v/file/bot%v/%v", Bot.URL, Bot.Token, file.FilePath))
+	if err != nil {
+		//Don't leak bot token from request URL
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return nil, "", err
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		_ = httpResponse.Body.Close()
+		return nil, "", fmt.Errorf("не удалось скачать файл: %v", httpResponse.Status)
+	}
+	return httpResponse.Body, file.FilePath, nil
+}
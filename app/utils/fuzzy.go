@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+//Score how well text matches query, higher is better, false if it doesn't match at all
+func FuzzyScore(query string, text string) (int, bool) {
+	query = strings.ToLower(query)
+	text = strings.ToLower(text)
+	switch {
+	case query == "":
+		return 0, true
+	case text == query:
+		return 1000, true
+	case strings.HasPrefix(text, query):
+		return 800 - utf8.RuneCountInString(text), true
+	case strings.Contains(text, query):
+		return 600 - utf8.RuneCountInString(text[:strings.Index(text, query)]), true
+	}
+	//Every query rune should be found in text in the same order, the less gaps the better
+	var gaps int
+	var pos int
+	textRunes := []rune(text)
+	for _, r := range query {
+		found := false
+		for ; pos < len(textRunes); pos++ {
+			if textRunes[pos] == r {
+				found = true
+				pos++
+				break
+			}
+			gaps++
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	return 300 - gaps, true
+}
+
+var latinLayout = []rune("`qwertyuiop[]asdfghjkl;'zxcvbnm,.")
+var cyrillicLayout = []rune("ёйцукенгшщзхъфывапролджэячсмитьбю")
+
+//Retype text as if it was typed with another keyboard layout
+func SwitchLayout(text string) string {
+	layout := make(map[rune]rune)
+	for i := range latinLayout {
+		layout[latinLayout[i]] = cyrillicLayout[i]
+		layout[cyrillicLayout[i]] = latinLayout[i]
+	}
+	runes := []rune(strings.ToLower(text))
+	for i, r := range runes {
+		if switched, ok := layout[r]; ok {
+			runes[i] = switched
+		}
+	}
+	return string(runes)
+}
+
+//Levenshtein distance between two strings in runes
+func Levenshtein(a string, b string) int {
+	ar := []rune(a)
+	br := []rune(b)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j] + 1
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+			if prev[j-1]+cost < cur[j] {
+				cur[j] = prev[j-1] + cost
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(br)]
+}
+
+//Find Get names closest to misspelled name, considering wrong keyboard layout
+func SimilarGets(name string, limit int) ([]string, error) {
+	var names []string
+	result := DB.Model(&Get{}).Scopes(NotExpired).Pluck("name", &names)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	variants := []string{strings.ToLower(name), SwitchLayout(name)}
+	distances := make(map[string]int)
+	for _, getName := range names {
+		for _, variant := range variants {
+			distance := Levenshtein(variant, getName)
+			maxDistance := utf8.RuneCountInString(variant) / 3
+			if maxDistance < 1 {
+				maxDistance = 1
+			}
+			if distance > maxDistance {
+				continue
+			}
+			if current, ok := distances[getName]; !ok || distance < current {
+				distances[getName] = distance
+			}
+		}
+	}
+	var similar []string
+	for getName := range distances {
+		similar = append(similar, getName)
+	}
+	sort.Slice(similar, func(i, j int) bool {
+		if distances[similar[i]] != distances[similar[j]] {
+			return distances[similar[i]] < distances[similar[j]]
+		}
+		return similar[i] < similar[j]
+	})
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, nil
+}
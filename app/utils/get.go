@@ -0,0 +1,243 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrUnknownGetType = errors.New("неизвестный тип гета")
+
+type AlbumItem struct {
+	Type      string `json:"type"`
+	FileID    string `json:"file_id"`
+	Caption   string `json:"caption,omitempty"`
+	MessageID int    `json:"-"`
+}
+
+type album struct {
+	Items    []AlbumItem
+	LastSeen time.Time
+}
+
+var albums = struct {
+	sync.Mutex
+	list map[string]*album
+}{list: make(map[string]*album)}
+
+//Remember media group item, so whole album can be saved with /set
+func RememberAlbumItem(m *tb.Message) {
+	if m.AlbumID == "" {
+		return
+	}
+	var item AlbumItem
+	switch {
+	case m.Photo != nil:
+		item = AlbumItem{Type: "Photo", FileID: m.Photo.FileID}
+	case m.Video != nil:
+		item = AlbumItem{Type: "Video", FileID: m.Video.FileID}
+	default:
+		return
+	}
+	item.Caption = m.Caption
+	item.MessageID = m.ID
+	albums.Lock()
+	defer albums.Unlock()
+	for id, a := range albums.list {
+		if time.Since(a.LastSeen) > 24*time.Hour {
+			delete(albums.list, id)
+		}
+	}
+	a, ok := albums.list[m.AlbumID]
+	if !ok {
+		a = &album{}
+		albums.list[m.AlbumID] = a
+	}
+	a.LastSeen = time.Now()
+	for _, e := range a.Items {
+		if e.MessageID == item.MessageID {
+			return
+		}
+	}
+	a.Items = append(a.Items, item)
+	sort.Slice(a.Items, func(i, j int) bool {
+		return a.Items[i].MessageID < a.Items[j].MessageID
+	})
+}
+
+//Return remembered media group items in message order
+func AlbumItems(albumID string) []AlbumItem {
+	albums.Lock()
+	defer albums.Unlock()
+	a, ok := albums.list[albumID]
+	if !ok {
+		return nil
+	}
+	return append([]AlbumItem{}, a.Items...)
+}
+
+type GetButton struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+//Keyboard of Get URL buttons, nil if Get has none
+func GetKeyboard(get Get) [][]tb.InlineButton {
+	var rows [][]GetButton
+	if get.Buttons == "" || json.Unmarshal([]byte(get.Buttons), &rows) != nil {
+		return nil
+	}
+	var keyboard [][]tb.InlineButton
+	for _, row := range rows {
+		var buttons []tb.InlineButton
+		for _, button := range row {
+			buttons = append(buttons, tb.InlineButton{Text: button.Text, URL: button.URL})
+		}
+		keyboard = append(keyboard, buttons)
+	}
+	return keyboard
+}
+
+//Hide expired Gets from query
+func NotExpired(db *gorm.DB) *gorm.DB {
+	return db.Where("(gets.expires_at IS NULL OR gets.expires_at > ?)", time.Now())
+}
+
+//Voice with caption, telebot's Voice doesn't have one
+type captionedVoice struct {
+	FileID  string
+	Caption string
+}
+
+func (v *captionedVoice) Send(b *tb.Bot, to tb.Recipient, opt *tb.SendOptions) (*tb.Message, error) {
+	params := map[string]string{
+		"chat_id":    to.Recipient(),
+		"voice":      v.FileID,
+		"caption":    v.Caption,
+		"parse_mode": tb.ModeHTML,
+	}
+	if opt != nil {
+		if opt.ReplyTo != nil && opt.ReplyTo.ID != 0 {
+			params["reply_to_message_id"] = strconv.Itoa(opt.ReplyTo.ID)
+		}
+		if opt.ReplyMarkup != nil {
+			replyMarkup, _ := json.Marshal(opt.ReplyMarkup)
+			params["reply_markup"] = string(replyMarkup)
+		}
+	}
+	data, err := b.Raw("sendVoice", params)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Result *tb.Message
+	}
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+//Send Get to recipient
+func SendGet(to tb.Recipient, get Get, options ...interface{}) error {
+	var err error
+	if keyboard := GetKeyboard(get); keyboard != nil {
+		options = append(options, &tb.ReplyMarkup{InlineKeyboard: keyboard})
+	}
+	switch {
+	case get.Type == "Animation":
+		_, err = Bot.Send(to, &tb.Animation{
+			File:    tb.File{FileID: get.Data},
+			Caption: get.Caption,
+		}, options...)
+	case get.Type == "Audio":
+		_, err = Bot.Send(to, &tb.Audio{
+			File:    tb.File{FileID: get.Data},
+			Caption: get.Caption,
+		}, options...)
+	case get.Type == "Photo":
+		_, err = Bot.Send(to, &tb.Photo{
+			File:    tb.File{FileID: get.Data},
+			Caption: get.Caption,
+		}, options...)
+	case get.Type == "Video":
+		_, err = Bot.Send(to, &tb.Video{
+			File:    tb.File{FileID: get.Data},
+			Caption: get.Caption,
+		}, options...)
+	case get.Type == "Voice":
+		_, err = Bot.Send(to, &captionedVoice{
+			FileID:  get.Data,
+			Caption: get.Caption,
+		}, options...)
+	case get.Type == "Document":
+		_, err = Bot.Send(to, &tb.Document{
+			File:    tb.File{FileID: get.Data},
+			Caption: get.Caption,
+		}, options...)
+	case get.Type == "Sticker":
+		_, err = Bot.Send(to, &tb.Sticker{
+			File: tb.File{FileID: get.Data},
+		}, options...)
+	case get.Type == "VideoNote":
+		_, err = Bot.Send(to, &tb.VideoNote{
+			File: tb.File{FileID: get.Data},
+		}, options...)
+	case get.Type == "Poll":
+		var poll tb.Poll
+		err = json.Unmarshal([]byte(get.Data), &poll)
+		if err != nil {
+			return err
+		}
+		_, err = Bot.Send(to, &poll, options...)
+	case get.Type == "Album":
+		var items []AlbumItem
+		err = json.Unmarshal([]byte(get.Data), &items)
+		if err != nil {
+			return err
+		}
+		var media tb.Album
+		for _, item := range items {
+			switch item.Type {
+			case "Photo":
+				media = append(media, &tb.Photo{File: tb.File{FileID: item.FileID}, Caption: item.Caption})
+			case "Video":
+				media = append(media, &tb.Video{File: tb.File{FileID: item.FileID}, Caption: item.Caption})
+			}
+		}
+		_, err = Bot.SendAlbum(to, media, options...)
+	case get.Type == "Text":
+		_, err = Bot.Send(to, get.Data, options...)
+	default:
+		return ErrUnknownGetType
+	}
+	return err
+}
+
+//Count Get usage by user for today
+func CountGetUsage(name string, userID int) error {
+	now := time.Now()
+	result := DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "get_name"}, {Name: "user_id"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("count + 1")}),
+	}).Create(&GetUsage{
+		GetName: name,
+		UserID:  userID,
+		Date:    time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local),
+		Count:   1,
+	})
+	return result.Error
+}
+
+//Order Get query by total usage, most used first
+func OrderByPopularity(db *gorm.DB) *gorm.DB {
+	return db.Joins("LEFT JOIN (SELECT get_name, SUM(count) AS uses FROM get_usages GROUP BY get_name) AS usage ON usage.get_name = gets.name").Order("COALESCE(usage.uses, 0) DESC").Order("gets.name")
+}
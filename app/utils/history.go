@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"sync"
+	"time"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+type recentMessage struct {
+	ID     int
+	UserID int
+	Date   time.Time
+}
+
+//Telegram doesn't allow bots to delete messages older than 48 hours
+const recentMessagesAge = 48 * time.Hour
+const recentMessagesLimit = 2000
+
+var recentMessages = struct {
+	sync.Mutex
+	list map[int64][]recentMessage
+}{list: make(map[int64][]recentMessage)}
+
+//Remember message ID of group message for /purge
+func RememberMessage(m *tb.Message) {
+	if m.Private() || m.Sender == nil {
+		return
+	}
+	recentMessages.Lock()
+	defer recentMessages.Unlock()
+	messages := append(recentMessages.list[m.Chat.ID], recentMessage{ID: m.ID, UserID: m.Sender.ID, Date: time.Now()})
+	for len(messages) != 0 && (len(messages) > recentMessagesLimit || time.Since(messages[0].Date) > recentMessagesAge) {
+		messages = messages[1:]
+	}
+	recentMessages.list[m.Chat.ID] = messages
+}
+
+//IDs of recent messages in chat, newest first, only user's messages if userID isn't zero
+func RecentMessages(chatID int64, userID int, limit int) []int {
+	recentMessages.Lock()
+	defer recentMessages.Unlock()
+	messages := recentMessages.list[chatID]
+	var ids []int
+	for i := len(messages) - 1; i >= 0 && len(ids) < limit; i-- {
+		if time.Since(messages[i].Date) > recentMessagesAge {
+			break
+		}
+		if userID == 0 || messages[i].UserID == userID {
+			ids = append(ids, messages[i].ID)
+		}
+	}
+	return ids
+}
+
+//Forget deleted messages
+func ForgetMessages(chatID int64, ids []int) {
+	deleted := make(map[int]bool)
+	for _, id := range ids {
+		deleted[id] = true
+	}
+	recentMessages.Lock()
+	defer recentMessages.Unlock()
+	var messages []recentMessage
+	for _, message := range recentMessages.list[chatID] {
+		if !deleted[message.ID] {
+			messages = append(messages, message)
+		}
+	}
+	recentMessages.list[chatID] = messages
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+var ModerationActionNames = map[string]string{
+	"ban":          "бан",
+	"unban":        "разбан",
+	"kick":         "исключение",
+	"mute":         "мьют",
+	"unmute":       "снятие мьюта",
+	"warn":         "предупреждение",
+	"unwarn":       "снятие предупреждения",
+	"kill":         "убийство",
+	"revive":       "возрождение",
+	"flood":        "мьют за флуд",
+	"delete":       "удаление сообщения",
+	"fban":         "бан в федерации",
+	"funban":       "разбан в федерации",
+	"fmute":        "мьют в федерации",
+	"purge":        "удаление сообщений",
+	"mutemedia":    "запрет медиа",
+	"mutestickers": "запрет стикеров и GIF",
+	"mutelinks":    "запрет ссылок",
+	"mutepolls":    "запрет опросов",
+}
+
+//Link to message in chat
+func MessageLink(m *tb.Message) string {
+	if m.Chat.Username != "" {
+		return fmt.Sprintf("https://t.me/%v/%v", m.Chat.Username, m.ID)
+	}
+	return fmt.Sprintf("https://t.me/c/%v/%v", strings.TrimPrefix(strconv.FormatInt(m.Chat.ID, 10), "-100"), m.ID)
+}
+
+//Get chat from config value, which is username or ID
+func ConfigChat(name string) (*tb.Chat, error) {
+	if _, err := strconv.ParseInt(name, 10, 64); err != nil {
+		name = "@" + strings.TrimPrefix(name, "@")
+	}
+	return Bot.ChatByID(name)
+}
+
+//Chat is private or it's staff chat, where staff-only information can be shown
+func IsStaffChat(chat *tb.Chat) bool {
+	if chat.Type == tb.ChatPrivate {
+		return true
+	}
+	staff := strings.TrimPrefix(Config.Reports.StaffChat, "@")
+	return staff != "" && (staff == chat.Username || staff == strconv.FormatInt(chat.ID, 10))
+}
+
+//Staff chat or private chats of moderators
+func StaffRecipients() ([]tb.Recipient, error) {
+	if Config.Reports.StaffChat != "" {
+		chat, err := ConfigChat(Config.Reports.StaffChat)
+		if err != nil {
+			return nil, err
+		}
+		return []tb.Recipient{chat}, nil
+	}
+	var users []tb.User
+	result := DB.Where("username IN ?", append(append([]string{}, Config.Telegram.Admins...), Config.Telegram.Moders...)).Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	var recipients []tb.Recipient
+	for i := range users {
+		recipients = append(recipients, &users[i])
+	}
+	return recipients, nil
+}
+
+//Restriction length in seconds, zero if restriction is forever or not timed
+func restrictionDuration(untildate int64) int64 {
+	if untildate-30 > time.Now().Unix() {
+		return untildate - time.Now().Unix()
+	}
+	return 0
+}
+
+//Record moderation action to database, track restriction and mirror action to log channel
+func LogModeration(m *tb.Message, actor *tb.User, target *tb.User, action string, untildate int64, reason string) error {
+	entry := ModerationAction{
+		Date:      time.Now(),
+		ChatID:    m.Chat.ID,
+		MessageID: m.ID,
+		ActorID:   actor.ID,
+		TargetID:  target.ID,
+		Action:    action,
+		Duration:  restrictionDuration(untildate),
+		Reason:    reason,
+	}
+	result := DB.Create(&entry)
+	if result.Error != nil {
+		return result.Error
+	}
+	var err error
+	switch action {
+	case "ban":
+		err = TrackRestriction(m.Chat, target, actor, "ban", untildate, reason)
+	case "mute", "kill", "flood":
+		err = TrackRestriction(m.Chat, target, actor, "mute", untildate, reason)
+	case "mutemedia", "mutestickers", "mutelinks", "mutepolls":
+		err = TrackRestriction(m.Chat, target, actor, strings.TrimPrefix(action, "mute"), untildate, reason)
+	case "unban":
+		err = ForgetRestriction(m.Chat, target, "ban")
+	case "unmute", "revive":
+		err = ForgetMutes(m.Chat, target)
+	}
+	if err != nil {
+		return err
+	}
+	if Config.Telegram.LogChannel == "" {
+		return nil
+	}
+	channel, err := ConfigChat(Config.Telegram.LogChannel)
+	if err != nil {
+		return err
+	}
+	text := fmt.Sprintf("#%v #id%v\nМодератор: %v\nПользователь: %v (<code>%v</code>)", action, target.ID, MentionUser(actor), MentionUser(target), target.ID)
+	if entry.Duration != 0 {
+		text += fmt.Sprintf("\nСрок: %v%v", FormatDuration(entry.Duration), RestrictionTimeMessage(untildate))
+	}
+	if reason != "" {
+		text += fmt.Sprintf("\nПричина: %v", html.EscapeString(reason))
+	}
+	switch action {
+	case "warn", "mute", "mutemedia", "mutestickers", "mutelinks", "mutepolls", "flood", "ban", "fban", "fmute":
+		notes, err := UserNotes(target.ID, 5)
+		if err != nil {
+			return err
+		}
+		if len(notes) != 0 {
+			text += fmt.Sprintf("\nЗаметки:\n%v", NotesMessage(notes))
+		}
+	}
+	//Actions outside of chat, like appeals, have no message
+	if m.ID != 0 {
+		text += fmt.Sprintf("\n<a href=\"%v\">Контекст</a>", MessageLink(m))
+	}
+	_, err = Bot.Send(channel, text, tb.NoPreview)
+	return err
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+//Staff notes about user, newest first, limit zero means all notes
+func UserNotes(userID int, limit int) ([]Note, error) {
+	var notes []Note
+	query := DB.Where(&Note{UserID: userID}).Order("date DESC")
+	if limit != 0 {
+		query = query.Limit(limit)
+	}
+	result := query.Find(&notes)
+	return notes, result.Error
+}
+
+//Note length in lists, longer notes are cut
+const noteListLength = 300
+
+//Notes list with dates and authors, one note per line
+func NotesMessage(notes []Note) string {
+	var authorIDs []int
+	for _, note := range notes {
+		authorIDs = append(authorIDs, note.AuthorID)
+	}
+	var users []tb.User
+	DB.Where("id IN ?", authorIDs).Find(&users)
+	authors := make(map[int]string)
+	for _, user := range users {
+		authors[user.ID] = UserName(&user)
+	}
+	var message string
+	for _, note := range notes {
+		author, ok := authors[note.AuthorID]
+		if !ok {
+			author = fmt.Sprint(note.AuthorID)
+		}
+		text := note.Text
+		if runes := []rune(text); len(runes) > noteListLength {
+			text = string(runes[:noteListLength]) + "…"
+		}
+		text = strings.ReplaceAll(text, "\n", " ")
+		message += fmt.Sprintf("#%v %v (%v): %v\n", note.ID, note.Date.Format("02.01.2006 15:04"), html.EscapeString(author), html.EscapeString(text))
+	}
+	return message
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+//Mute, kick or ban user in chat of context message, announce it and log it
+func PunishUser(m *tb.Message, actor *tb.User, target *tb.User, action string, untildate int64, reason string) error {
+	return punishUser(m, actor, target, action, untildate, reason, nil)
+}
+
+//Punish user and attach undo button to announce, if undo action is given
+func punishUser(m *tb.Message, actor *tb.User, target *tb.User, action string, untildate int64, reason string, undo *UndoAction) error {
+	member, err := Bot.ChatMemberOf(m.Chat, target)
+	if err != nil {
+		return err
+	}
+	var message string
+	switch action {
+	case "mute":
+		err = SaveRights(m.Chat, member)
+		if err != nil {
+			return err
+		}
+		member.CanSendMessages = false
+		member.RestrictedUntil = untildate
+		err = Bot.Restrict(m.Chat, member)
+		message = fmt.Sprintf("Пользователь %v больше не может отправлять сообщения%v.", MentionUser(target), RestrictionTimeMessage(untildate))
+	case "kick":
+		untildate = 0
+		member.RestrictedUntil = time.Now().Unix() + 60
+		err = Bot.Ban(m.Chat, member)
+		if err == nil {
+			err = Bot.Unban(m.Chat, target)
+		}
+		message = fmt.Sprintf("Пользователь %v исключен.", MentionUser(target))
+	default:
+		action = "ban"
+		member.RestrictedUntil = untildate
+		err = Bot.Ban(m.Chat, member)
+		message = fmt.Sprintf("Пользователь %v забанен%v.", MentionUser(target), RestrictionTimeMessage(untildate))
+	}
+	if err != nil {
+		return err
+	}
+	//Punishment is already done, so it's logged even if notice isn't sent
+	err = LogModeration(m, actor, target, action, untildate, reason)
+	if err != nil {
+		ErrorReporting(err, m)
+	}
+	var options []interface{}
+	if undo != nil {
+		undo.Punishment = action
+		options = append(options, RememberUndo(*undo))
+	}
+	_, err = Bot.Send(m.Chat, message+ReasonMessage(reason), options...)
+	return err
+}
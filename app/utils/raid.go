@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"sync"
+	"time"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+	"gorm.io/gorm/clause"
+)
+
+type raidJoin struct {
+	User *tb.User
+	Date time.Time
+}
+
+var raid = struct {
+	sync.Mutex
+	joins       []raidJoin
+	active      bool
+	chat        *tb.Chat
+	started     time.Time
+	lastJoin    time.Time
+	joined      []*tb.User
+	permissions *tb.Rights
+}{}
+
+//Remember join and check join rate, returns true if raid mode is active and joined user should be stopped
+func RaidJoin(chat *tb.Chat, user *tb.User) bool {
+	//Raid state is kept for main chat only
+	if chat.Username != Config.Telegram.Chat {
+		return false
+	}
+	raid.Lock()
+	if raid.active {
+		raid.joined = append(raid.joined, user)
+		raid.lastJoin = time.Now()
+		raid.Unlock()
+		return true
+	}
+	window := time.Duration(Config.Antiraid.Window) * time.Second
+	var joins []raidJoin
+	for _, join := range raid.joins {
+		if time.Since(join.Date) < window {
+			joins = append(joins, join)
+		}
+	}
+	raid.joins = append(joins, raidJoin{User: user, Date: time.Now()})
+	if !Config.Antiraid.Enabled || len(raid.joins) < Config.Antiraid.Joins {
+		raid.Unlock()
+		return false
+	}
+	joins = raid.joins
+	raid.joins = nil
+	raid.Unlock()
+	err := RaidStart(chat, fmt.Sprintf("%v входов за %v", len(joins), FormatDuration(int64(Config.Antiraid.Window))))
+	if err != nil {
+		log.Println(err.Error())
+	}
+	//Users, who triggered raid mode, joined during raid too
+	raid.Lock()
+	var joined []*tb.User
+	for _, join := range joins {
+		joined = append(joined, join.User)
+	}
+	raid.joined = append(joined, raid.joined...)
+	raid.started = joins[0].Date
+	raid.Unlock()
+	return true
+}
+
+//Raid mode is active
+func RaidActive() bool {
+	raid.Lock()
+	defer raid.Unlock()
+	return raid.active
+}
+
+//Users, who joined during current or last raid, raid chat and raid start time
+func RaidJoiners() ([]*tb.User, *tb.Chat, time.Time) {
+	raid.Lock()
+	defer raid.Unlock()
+	return append([]*tb.User{}, raid.joined...), raid.chat, raid.started
+}
+
+//Set default permissions of chat, telebot sends them without permissions object
+func setChatPermissions(chat *tb.Chat, rights tb.Rights) error {
+	_, err := Bot.Raw("setChatPermissions", map[string]interface{}{
+		"chat_id":     chat.Recipient(),
+		"permissions": rights,
+	})
+	return err
+}
+
+//Enter raid mode, tighten chat permissions and alert staff
+func RaidStart(chat *tb.Chat, reason string) error {
+	raid.Lock()
+	if raid.active {
+		raid.Unlock()
+		return nil
+	}
+	raid.active = true
+	raid.chat = chat
+	raid.started = time.Now()
+	raid.lastJoin = time.Now()
+	raid.joined = nil
+	raid.permissions = nil
+	raid.Unlock()
+	go raidWatch()
+	var restricted bool
+	var previous RaidState
+	DB.Where(&RaidState{ChatID: chat.ID}).Limit(1).Find(&previous)
+	state := RaidState{ChatID: chat.ID, Started: time.Now()}
+	full, err := Bot.ChatByID(fmt.Sprint(chat.ID))
+	if err == nil && full.Permissions != nil {
+		permissions, _ := json.Marshal(full.Permissions)
+		state.Permissions = string(permissions)
+		//Permissions weren't restored after previous raid, current ones are raid permissions
+		if previous.Permissions != "" {
+			state.Permissions = previous.Permissions
+			_ = json.Unmarshal([]byte(previous.Permissions), full.Permissions)
+		}
+		//Save permissions before changing them, so they can be restored even after restart
+		result := DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(&state)
+		err = result.Error
+		if err == nil {
+			err = setChatPermissions(chat, tb.Rights{CanSendMessages: true})
+		}
+		if err == nil {
+			restricted = true
+			raid.Lock()
+			raid.permissions = full.Permissions
+			raid.Unlock()
+		} else {
+			state.Permissions = previous.Permissions
+		}
+	}
+	if !restricted {
+		result := DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(&state)
+		if result.Error != nil {
+			log.Println(result.Error.Error())
+		}
+	}
+	action := "забанены"
+	if Config.Antiraid.Action == "restrict" {
+		action = "ограничены без капчи"
+	}
+	text := fmt.Sprintf("🚨 Рейд в чате %v: %v.\nНовые участники будут %v.", html.EscapeString(chat.Title), html.EscapeString(reason), action)
+	if restricted {
+		text += "\nМедиа и приглашения в чате отключены."
+	}
+	text += "\nВыключить режим рейда: <code>/raid off</code>\nЗабанить всех вошедших во время рейда: <code>/raid cleanup</code>"
+	if err != nil {
+		text += fmt.Sprintf("\n\nНе удалось ограничить права в чате:\n<code>%v</code>", html.EscapeString(err.Error()))
+	}
+	raidAlert(text)
+	return err
+}
+
+//Continue raid mode, that was active before restart, or finish it if raid is already over
+func RaidResume() {
+	var states []RaidState
+	result := DB.Find(&states)
+	if result.Error != nil {
+		log.Println(result.Error.Error())
+		return
+	}
+	for _, state := range states {
+		chat, err := Bot.ChatByID(fmt.Sprint(state.ChatID))
+		if err != nil {
+			log.Println(err.Error())
+			continue
+		}
+		var permissions *tb.Rights
+		if state.Permissions != "" {
+			permissions = &tb.Rights{}
+			err = json.Unmarshal([]byte(state.Permissions), permissions)
+			if err != nil {
+				log.Println(err.Error())
+				permissions = nil
+			}
+		}
+		raid.Lock()
+		if raid.active {
+			raid.Unlock()
+			continue
+		}
+		raid.active = true
+		raid.chat = chat
+		raid.started = state.Started
+		//Joins during downtime are unknown, so quiet period starts from restart
+		raid.lastJoin = time.Now()
+		raid.permissions = permissions
+		raid.Unlock()
+		go raidWatch()
+		raidAlert(fmt.Sprintf("Бот перезапущен во время рейда в чате %v, режим рейда продолжается.\nВыключить режим рейда: <code>/raid off</code>", html.EscapeString(chat.Title)))
+	}
+}
+
+//Leave raid mode and restore chat permissions
+func RaidEnd(reason string) error {
+	raid.Lock()
+	if !raid.active {
+		raid.Unlock()
+		return nil
+	}
+	raid.active = false
+	chat := raid.chat
+	permissions := raid.permissions
+	joined := len(raid.joined)
+	raid.Unlock()
+	var err error
+	if permissions != nil {
+		err = setChatPermissions(chat, *permissions)
+	}
+	//Keep state with permissions, if they weren't restored, so they are restored after restart
+	if err == nil {
+		result := DB.Delete(&RaidState{ChatID: chat.ID})
+		if result.Error != nil {
+			log.Println(result.Error.Error())
+		}
+	}
+	raidAlert(fmt.Sprintf("Режим рейда в чате %v выключен: %v.\nВо время рейда вошло: %v.", html.EscapeString(chat.Title), html.EscapeString(reason), joined))
+	return err
+}
+
+func raidAlert(text string) {
+	recipients, err := StaffRecipients()
+	if err != nil {
+		log.Println(err.Error())
+	}
+	for _, recipient := range recipients {
+		_, err = Bot.Send(recipient, text)
+		if err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+//End raid mode after quiet period
+func raidWatch() {
+	quiet := time.Duration(Config.Antiraid.Quiet) * time.Second
+	for {
+		time.Sleep(10 * time.Second)
+		raid.Lock()
+		active := raid.active
+		lastJoin := raid.lastJoin
+		raid.Unlock()
+		if !active {
+			return
+		}
+		if time.Since(lastJoin) > quiet {
+			err := RaidEnd(fmt.Sprintf("нет новых участников %v", FormatDuration(int64(Config.Antiraid.Quiet))))
+			if err != nil {
+				log.Println(err.Error())
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+	"gorm.io/gorm/clause"
+)
+
+//Remember restriction applied by bot, kind is mute or ban
+func TrackRestriction(chat *tb.Chat, target *tb.User, actor *tb.User, kind string, untildate int64, reason string) error {
+	restriction := Restriction{
+		ChatID:  chat.ID,
+		UserID:  target.ID,
+		Type:    kind,
+		ActorID: actor.ID,
+		Reason:  reason,
+		Date:    time.Now(),
+	}
+	if restrictionDuration(untildate) != 0 {
+		restriction.UntilDate = untildate
+	}
+	result := DB.Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(&restriction)
+	return result.Error
+}
+
+//Forget restriction, when it's lifted
+func ForgetRestriction(chat *tb.Chat, target *tb.User, kind string) error {
+	result := DB.Delete(&Restriction{ChatID: chat.ID, UserID: target.ID, Type: kind})
+	return result.Error
+}
+
+//Forget all mutes of user, when they are unmuted
+func ForgetMutes(chat *tb.Chat, target *tb.User) error {
+	result := DB.Where("chat_id = ? AND user_id = ? AND type != ?", chat.ID, target.ID, "ban").Delete(&Restriction{})
+	return result.Error
+}
+
+//Partial mute modes, user can still send text
+var MuteModes = map[string]string{
+	"media":    "медиа",
+	"stickers": "стикеры и GIF",
+	"links":    "ссылки",
+	"polls":    "опросы",
+}
+
+//Send rights, which member has now, rights of regular members are default permissions of chat
+func SendRights(chat *tb.Chat, member *tb.ChatMember) (tb.Rights, error) {
+	rights := tb.Rights{
+		CanSendMessages: true,
+		CanSendMedia:    true,
+		CanSendPolls:    true,
+		CanSendOther:    true,
+		CanAddPreviews:  true,
+	}
+	switch member.Role {
+	case tb.Restricted:
+		return member.Rights, nil
+	case tb.Member:
+		full, err := Bot.ChatByID(fmt.Sprint(chat.ID))
+		if err != nil {
+			return rights, err
+		}
+		if full.Permissions != nil {
+			rights.CanSendMessages = full.Permissions.CanSendMessages
+			rights.CanSendMedia = full.Permissions.CanSendMedia
+			rights.CanSendPolls = full.Permissions.CanSendPolls
+			rights.CanSendOther = full.Permissions.CanSendOther
+			rights.CanAddPreviews = full.Permissions.CanAddPreviews
+		}
+	}
+	return rights, nil
+}
+
+//Take away rights of mute mode, links are deleted by bot, but previews are disabled too
+func ApplyMuteMode(rights *tb.Rights, mode string) {
+	switch mode {
+	case "media":
+		rights.CanSendMedia = false
+		rights.CanSendOther = false
+		rights.CanAddPreviews = false
+	case "stickers":
+		rights.CanSendOther = false
+	case "links":
+		rights.CanAddPreviews = false
+	case "polls":
+		rights.CanSendPolls = false
+	default:
+		rights.CanSendMessages = false
+	}
+}
+
+//Remember rights of member before the first mute, later mutes keep them
+func SaveRights(chat *tb.Chat, member *tb.ChatMember) error {
+	rights, err := SendRights(chat, member)
+	if err != nil {
+		return err
+	}
+	result := DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&SavedRights{
+		ChatID:          chat.ID,
+		UserID:          member.User.ID,
+		Restricted:      member.Role == tb.Restricted,
+		RestrictedUntil: member.RestrictedUntil,
+		CanSendMessages: rights.CanSendMessages,
+		CanSendMedia:    rights.CanSendMedia,
+		CanSendPolls:    rights.CanSendPolls,
+		CanSendOther:    rights.CanSendOther,
+		CanAddPreviews:  rights.CanAddPreviews,
+	})
+	return result.Error
+}
+
+//Give member back rights, which they had before the first mute
+func RestoreRights(chat *tb.Chat, member *tb.ChatMember) error {
+	var saved SavedRights
+	result := DB.Where(&SavedRights{ChatID: chat.ID, UserID: member.User.ID}).Limit(1).Find(&saved)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 0 && saved.Restricted && (saved.RestrictedUntil == 0 || saved.RestrictedUntil > time.Now().Unix()) {
+		member.CanSendMessages = saved.CanSendMessages
+		member.CanSendMedia = saved.CanSendMedia
+		member.CanSendPolls = saved.CanSendPolls
+		member.CanSendOther = saved.CanSendOther
+		member.CanAddPreviews = saved.CanAddPreviews
+		member.RestrictedUntil = saved.RestrictedUntil
+	} else {
+		member.CanSendMessages = true
+		member.CanSendMedia = true
+		member.CanSendPolls = true
+		member.CanSendOther = true
+		member.CanAddPreviews = true
+		member.RestrictedUntil = time.Now().Unix() + 60
+	}
+	err := Bot.Restrict(chat, member)
+	if err != nil {
+		return err
+	}
+	return ForgetRights(chat, member.User)
+}
+
+//Forget saved rights, when restriction is over
+func ForgetRights(chat *tb.Chat, target *tb.User) error {
+	result := DB.Delete(&SavedRights{ChatID: chat.ID, UserID: target.ID})
+	return result.Error
+}
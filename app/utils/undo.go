@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+var UndoSelector = tb.ReplyMarkup{}
+var UndoButton = UndoSelector.Data("↩️ Отменить", "undo")
+
+//Moderation action, which can be reverted with undo button
+type UndoAction struct {
+	Chat   *tb.Chat
+	Target *tb.User
+	//ban, mute, kick, kill or warn
+	Action string
+	//warning given by warn
+	WarningID int
+	//ladder step action applied by warn
+	Punishment string
+	Date       time.Time
+}
+
+var undoActions = struct {
+	sync.Mutex
+	list map[int]UndoAction
+	last int
+}{list: make(map[int]UndoAction)}
+
+func undoWindow() time.Duration {
+	return time.Duration(Config.Telegram.UndoWindow) * time.Second
+}
+
+//Remember action for undo and get markup with undo button
+func RememberUndo(action UndoAction) *tb.ReplyMarkup {
+	undoActions.Lock()
+	defer undoActions.Unlock()
+	for id, e := range undoActions.list {
+		if time.Since(e.Date) > undoWindow() {
+			delete(undoActions.list, id)
+		}
+	}
+	action.Date = time.Now()
+	undoActions.last++
+	undoActions.list[undoActions.last] = action
+	markup := &tb.ReplyMarkup{}
+	markup.Inline(markup.Row(markup.Data(UndoButton.Text, UndoButton.Unique, strconv.Itoa(undoActions.last))))
+	return markup
+}
+
+//Take remembered action, false if it's unknown or undo time is over
+func TakeUndo(id int) (UndoAction, bool) {
+	undoActions.Lock()
+	defer undoActions.Unlock()
+	action, ok := undoActions.list[id]
+	delete(undoActions.list, id)
+	if !ok || time.Since(action.Date) > undoWindow() {
+		return action, false
+	}
+	return action, true
+}
+
+//Unban user only if they are still banned, telebot's Unban would kick user, who already came back
+func UnbanIfBanned(chat *tb.Chat, user *tb.User) error {
+	_, err := Bot.Raw("unbanChatMember", map[string]interface{}{
+		"chat_id":        chat.Recipient(),
+		"user_id":        user.ID,
+		"only_if_banned": true,
+	})
+	return err
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+var UserChoiceSelector = tb.ReplyMarkup{}
+var UserChoiceButton = UserChoiceSelector.Data("", "userchoice")
+
+//Command waits for user choice, caller shouldn't reply anything
+var ErrUserChoice = errors.New("нужно выбрать пользователя")
+
+//Several users or only partial matches are found by name query, so sender should choose
+type AmbiguousUserError struct {
+	Query string
+	Users []tb.User
+}
+
+func (e *AmbiguousUserError) Error() string {
+	return fmt.Sprintf("нужно уточнить пользователя по запросу «%v», найдено: %v", e.Query, len(e.Users))
+}
+
+type userChoice struct {
+	Message tb.Message
+	//index of command field with user
+	Arg  int
+	Date time.Time
+}
+
+var userChoices = struct {
+	sync.Mutex
+	list map[int]userChoice
+	last int
+}{list: make(map[int]userChoice)}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+//Find users by name or username, exact matches or, if there are none, partial ones, true if matches are exact
+func FindUsersByName(query string) ([]tb.User, bool, error) {
+	query = strings.ToLower(query)
+	//SQLite lowercases only ASCII, so name is also searched as it's usually written
+	runes := []rune(query)
+	variants := []string{query, strings.ToUpper(string(runes[:1])) + string(runes[1:]), strings.ToUpper(query)}
+	var conditions []string
+	var args []interface{}
+	for _, variant := range variants {
+		pattern := "%" + likeEscaper.Replace(variant) + "%"
+		conditions = append(conditions, "LOWER(first_name || ' ' || last_name) LIKE ? ESCAPE '\\' OR LOWER(username) LIKE ? ESCAPE '\\'")
+		args = append(args, pattern, pattern)
+	}
+	var users []tb.User
+	result := DB.Where(strings.Join(conditions, " OR "), args...).Find(&users)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	var exact, partial []tb.User
+	for _, user := range users {
+		name := strings.ToLower(UserFullName(&user))
+		switch {
+		case name == query || strings.ToLower(user.FirstName) == query || strings.ToLower(user.Username) == query:
+			exact = append(exact, user)
+		case strings.Contains(name, query) || strings.Contains(strings.ToLower(user.Username), query):
+			partial = append(partial, user)
+		}
+	}
+	if len(exact) != 0 {
+		return exact, true, nil
+	}
+	sort.Slice(partial, func(i, j int) bool {
+		return UserFullName(&partial[i]) < UserFullName(&partial[j])
+	})
+	return partial, false, nil
+}
+
+//Ask command sender to choose target from found users, command will be repeated with chosen user's ID
+func askUserChoice(m *tb.Message, users []tb.User, arg int) error {
+	userChoices.Lock()
+	for id, e := range userChoices.list {
+		if time.Since(e.Date) > time.Hour {
+			delete(userChoices.list, id)
+		}
+	}
+	userChoices.last++
+	id := strconv.Itoa(userChoices.last)
+	userChoices.list[userChoices.last] = userChoice{Message: *m, Arg: arg, Date: time.Now()}
+	userChoices.Unlock()
+	if len(users) > 5 {
+		users = users[:5]
+	}
+	markup := &tb.ReplyMarkup{}
+	var rows []tb.Row
+	for _, user := range users {
+		text := UserFullName(&user)
+		if user.Username != "" {
+			text += fmt.Sprintf(" (@%v)", user.Username)
+		}
+		rows = append(rows, markup.Row(markup.Data(text, UserChoiceButton.Unique, id, strconv.Itoa(user.ID))))
+	}
+	markup.Inline(rows...)
+	_, err := Bot.Reply(m, "Уточни, какого пользователя ты имеешь в виду:", markup)
+	return err
+}
+
+//Take command message, that waits for user choice of sender, with chosen user's ID in place of name
+func TakeUserChoice(id int, sender *tb.User, userID string) (tb.Message, error) {
+	userChoices.Lock()
+	defer userChoices.Unlock()
+	e, ok := userChoices.list[id]
+	if !ok {
+		return e.Message, errors.New("команда устарела, отправь её ещё раз")
+	}
+	if e.Message.Sender.ID != sender.ID {
+		return e.Message, errors.New("выбрать пользователя может только автор команды")
+	}
+	delete(userChoices.list, id)
+	text := strings.Fields(e.Message.Text)
+	text[e.Arg] = userID
+	e.Message.Text = strings.Join(text, " ")
+	e.Message.Entities = nil
+	return e.Message, nil
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	tb "gopkg.in/tucnak/telebot.v2"
+)
+
+//User's warnings, that aren't expired or revoked yet, oldest first
+func ActiveWarnings(userID int) ([]Warning, error) {
+	var warnings []Warning
+	result := DB.Where("user_id = ? AND expires_at > ? AND revoked_at IS NULL", userID, time.Now()).Order("date").Find(&warnings)
+	return warnings, result.Error
+}
+
+//Ladder step for warn amount, the highest one is used after ladder ends
+func WarnStepFor(amount int) *WarnStep {
+	var step *WarnStep
+	for i, e := range Config.Warns.Ladder {
+		if e.Warns <= amount && (step == nil || e.Warns > step.Warns) {
+			step = &Config.Warns.Ladder[i]
+		}
+	}
+	if step != nil && step.Warns != amount && NextWarnStep(amount) != nil {
+		return nil
+	}
+	return step
+}
+
+//Next ladder step after warn amount, nil if ladder ended
+func NextWarnStep(amount int) *WarnStep {
+	var step *WarnStep
+	for i, e := range Config.Warns.Ladder {
+		if e.Warns > amount && (step == nil || e.Warns < step.Warns) {
+			step = &Config.Warns.Ladder[i]
+		}
+	}
+	return step
+}
+
+//Human readable description of ladder step
+func WarnStepMessage(step *WarnStep) string {
+	var message string
+	switch step.Action {
+	case "mute":
+		message = "мьют"
+	case "kick":
+		return "исключение из чата"
+	default:
+		message = "бан"
+	}
+	duration, err := ParseDuration(step.Duration)
+	if err != nil || duration == 0 {
+		return message + " навсегда"
+	}
+	return fmt.Sprintf("%v на %v", message, FormatDuration(int64(duration.Seconds())))
+}
+
+//Plural form of warning amount
+func WarnAmountMessage(amount int) string {
+	word := "предупреждений"
+	switch {
+	case amount%100 >= 11 && amount%100 <= 14:
+	case amount%10 == 1:
+		word = "предупреждение"
+	case amount%10 >= 2 && amount%10 <= 4:
+		word = "предупреждения"
+	}
+	return fmt.Sprintf("%v %v", amount, word)
+}
+
+//Give warning to user and apply warn ladder step, m is the context message in target chat
+func WarnUser(m *tb.Message, issuer *tb.User, target *tb.User, reason string) error {
+	warning := Warning{
+		UserID:    target.ID,
+		IssuerID:  issuer.ID,
+		Reason:    reason,
+		Date:      time.Now(),
+		ExpiresAt: time.Now().AddDate(0, 0, Config.Warns.ExpireDays),
+	}
+	result := DB.Create(&warning)
+	if result.Error != nil {
+		return result.Error
+	}
+	err := LogModeration(m, issuer, target, "warn", 0, reason)
+	if err != nil {
+		ErrorReporting(err, m)
+	}
+	warnings, err := ActiveWarnings(target.ID)
+	if err != nil {
+		return err
+	}
+	amount := len(warnings)
+	step := WarnStepFor(amount)
+	if step == nil {
+		message := fmt.Sprintf("%v, у тебя %v.%v", MentionUser(target), WarnAmountMessage(amount), ReasonMessage(reason))
+		if next := NextWarnStep(amount); next != nil {
+			message += fmt.Sprintf("\nЕсли за %v дней наберешь %v, то получишь %v.", Config.Warns.ExpireDays, WarnAmountMessage(next.Warns), WarnStepMessage(next))
+		}
+		_, err := Bot.Send(m.Chat, message, RememberUndo(UndoAction{Chat: m.Chat, Target: target, Action: "warn", WarningID: warning.ID}))
+		return err
+	}
+	untildate := time.Now().Unix()
+	duration, err := ParseDuration(step.Duration)
+	if err == nil {
+		untildate += int64(duration.Seconds())
+	}
+	return punishUser(m, issuer, target, step.Action, untildate, fmt.Sprintf("набрано %v", WarnAmountMessage(amount)), &UndoAction{Chat: m.Chat, Target: target, Action: "warn", WarningID: warning.ID})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/NexonSU/telegram-go-chatbot/app/utils"
 	tb "gopkg.in/tucnak/telebot.v2"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -23,6 +24,18 @@ func OnClickCorrectButton(c *tb.Callback) {
 				utils.ErrorReporting(err, c.Message)
 				return
 			}
+			if utils.Config.Probation.Enabled && (utils.Config.Probation.Hours > 0 || utils.Config.Probation.Messages > 0) {
+				probation := utils.Probation{UserID: c.Sender.ID, ChatID: Border.Chat.ID}
+				if utils.Config.Probation.Hours > 0 {
+					probation.Until = time.Now().Add(time.Duration(utils.Config.Probation.Hours) * time.Hour)
+				}
+				result := utils.DB.Clauses(clause.OnConflict{
+					UpdateAll: true,
+				}).Create(&probation)
+				if result.Error != nil {
+					utils.ErrorReporting(result.Error, c.Message)
+				}
+			}
 			Border.Users[i].Status = "accepted"
 			Border.NeedUpdate = true
 			err = utils.Bot.Respond(c, &tb.CallbackResponse{Text: fmt.Sprintf("Добро пожаловать, %v!\nТеперь у тебя есть доступ к чату.", utils.UserFullName(c.Sender)), ShowAlert: true})
@@ -0,0 +1,54 @@
+package welcome
+
+import (
+	"fmt"
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"time"
+)
+
+//Ban joined users, who are in federation ban list, true if all of them were banned
+func federationCheck(m *tb.Message) bool {
+	users := joinedUsers(m)
+	if !utils.InFederation(m.Chat.ID) {
+		return false
+	}
+	banned := 0
+	for i := range users {
+		if federationBan(m, &users[i]) {
+			banned++
+		}
+	}
+	if banned != len(users) {
+		return false
+	}
+	_ = utils.Bot.Delete(m)
+	return true
+}
+
+//Ban joined user, if user is in federation ban list
+func federationBan(m *tb.Message, user *tb.User) bool {
+	var ban utils.FederationBan
+	result := utils.DB.Where(&utils.FederationBan{UserID: user.ID}).Limit(1).Find(&ban)
+	if result.RowsAffected == 0 {
+		return false
+	}
+	if ban.UntilDate != 0 && ban.UntilDate < time.Now().Unix() {
+		utils.DB.Delete(&ban)
+		return false
+	}
+	err := utils.Bot.Ban(m.Chat, &tb.ChatMember{User: user, RestrictedUntil: ban.UntilDate})
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return false
+	}
+	reason := "бан в федерации"
+	if ban.Reason != "" {
+		reason = fmt.Sprintf("%v: %v", reason, ban.Reason)
+	}
+	err = utils.LogModeration(m, utils.Bot.Me, user, "ban", ban.UntilDate, reason)
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	return true
+}
@@ -32,10 +32,28 @@ var Border JoinBorder
 
 var arabicSymbols, _ = regexp.Compile("[\u0600-\u06ff]|[\u0750-\u077f]|[\ufb50-\ufbc1]|[\ufbd3-\ufd3f]|[\ufd50-\ufd8f]|[\ufd92-\ufdc7]|[\ufe70-\ufefc]|[\uFDF0-\uFDFD]")
 
+//Users, who joined with message, several users can be added at once
+func joinedUsers(m *tb.Message) []tb.User {
+	if len(m.UsersJoined) != 0 {
+		return m.UsersJoined
+	}
+	user := m.UserJoined
+	if user == nil {
+		user = m.Sender
+	}
+	return []tb.User{*user}
+}
+
 func OnJoin(m *tb.Message) {
+	if federationCheck(m) {
+		return
+	}
 	if m.Chat.Username != utils.Config.Telegram.Chat {
 		return
 	}
+	if raidCheck(m) {
+		return
+	}
 	err := utils.Bot.Delete(m)
 	if err != nil {
 		utils.ErrorReporting(err, m)
@@ -67,6 +85,10 @@ func OnJoin(m *tb.Message) {
 			utils.ErrorReporting(err, m)
 			return
 		}
+		err = utils.LogModeration(m, utils.Bot.Me, m.Sender, "ban", 0, "арабская вязь в имени")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+		}
 		for i, e := range Border.Users {
 			if e.User.ID == m.Sender.ID {
 				Border.Users[i].Status = "banned"
@@ -82,6 +104,10 @@ func OnJoin(m *tb.Message) {
 			utils.ErrorReporting(err, m)
 			return
 		}
+		err = utils.LogModeration(m, utils.Bot.Me, m.Sender, "ban", 0, "ICSM в имени")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+		}
 		for i, e := range Border.Users {
 			if e.User.ID == m.Sender.ID {
 				Border.Users[i].Status = "banned"
@@ -106,6 +132,10 @@ func OnJoin(m *tb.Message) {
 			utils.ErrorReporting(err, m)
 			return
 		}
+		err = utils.LogModeration(m, utils.Bot.Me, m.Sender, "ban", 0, "Combot Anti-Spam")
+		if err != nil {
+			utils.ErrorReporting(err, m)
+		}
 		for i, e := range Border.Users {
 			if e.User.ID == m.Sender.ID {
 				Border.Users[i].Status = "banned"
@@ -62,6 +62,10 @@ func JoinMessageUpdate() error {
 				if err != nil {
 					continue
 				}
+				err = utils.LogModeration(Border.Message, utils.Bot.Me, user.User, "ban", 0, "не прошел проверку")
+				if err != nil {
+					log.Println(err.Error())
+				}
 				Border.Users[i].Status = "banned"
 				user.Status = "banned"
 				Border.Users[i].Reason = "не прошел проверку"
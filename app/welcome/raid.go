@@ -0,0 +1,52 @@
+package welcome
+
+import (
+	"github.com/NexonSU/telegram-go-chatbot/app/utils"
+	tb "gopkg.in/tucnak/telebot.v2"
+	"log"
+)
+
+//Count joins for raid detection and stop joined users without captcha during raid, true if all users were stopped
+func raidCheck(m *tb.Message) bool {
+	users := joinedUsers(m)
+	stopped := 0
+	for i := range users {
+		if raidStop(m, &users[i]) {
+			stopped++
+		}
+	}
+	if stopped != len(users) {
+		return false
+	}
+	_ = utils.Bot.Delete(m)
+	return true
+}
+
+//Count user's join and ban or restrict user, if raid mode is active, true if user was stopped
+func raidStop(m *tb.Message, user *tb.User) bool {
+	if !utils.RaidJoin(m.Chat, user) {
+		return false
+	}
+	ChatMember := &tb.ChatMember{
+		Rights: tb.Rights{CanSendMessages: false},
+		User:   user,
+	}
+	action := "ban"
+	var err error
+	if utils.Config.Antiraid.Action == "restrict" {
+		action = "mute"
+		err = utils.Bot.Restrict(m.Chat, ChatMember)
+	} else {
+		err = utils.Bot.Ban(m.Chat, ChatMember)
+	}
+	if err != nil {
+		utils.ErrorReporting(err, m)
+		return true
+	}
+	log.Printf("Raid mode: %v for new user in %v (%v). ID: %v. Login: %v.", action, m.Chat.Title, m.Chat.ID, user.ID, utils.UserName(user))
+	err = utils.LogModeration(m, utils.Bot.Me, user, action, 0, "рейд")
+	if err != nil {
+		utils.ErrorReporting(err, m)
+	}
+	return true
+}
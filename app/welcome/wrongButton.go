@@ -14,11 +14,16 @@ func OnClickWrongButton(c *tb.Callback) {
 				utils.ErrorReporting(err, c.Message)
 				return
 			}
-			err = utils.Bot.Ban(Border.Chat, &tb.ChatMember{User: c.Sender, RestrictedUntil: time.Now().Unix() + 7200})
+			untildate := time.Now().Unix() + 7200
+			err = utils.Bot.Ban(Border.Chat, &tb.ChatMember{User: c.Sender, RestrictedUntil: untildate})
 			if err != nil {
 				utils.ErrorReporting(err, c.Message)
 				return
 			}
+			err = utils.LogModeration(c.Message, utils.Bot.Me, c.Sender, "ban", untildate, "неверный ответ")
+			if err != nil {
+				utils.ErrorReporting(err, c.Message)
+			}
 			Border.Users[i].Status = "banned"
 			Border.Users[i].Reason = "неверный ответ"
 			Border.NeedUpdate = true
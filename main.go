@@ -14,6 +14,13 @@ func main() {
 	utils.Bot.Handle("/debug", commands.Debug)
 	utils.Bot.Handle("/get", commands.Get)
 	utils.Bot.Handle("/getall", commands.Getall)
+	utils.Bot.Handle("/getcat", commands.Getcat)
+	utils.Bot.Handle("/getbuttons", commands.Getbuttons)
+	utils.Bot.Handle("/getexpire", commands.Getexpire)
+	utils.Bot.Handle("/gettop", commands.Gettop)
+	utils.Bot.Handle("/getstale", commands.Getstale)
+	utils.Bot.Handle("/getexport", commands.Getexport)
+	utils.Bot.Handle("/getimport", commands.Getimport)
 	utils.Bot.Handle("/set", commands.Set)
 	utils.Bot.Handle("/del", commands.Del)
 	utils.Bot.Handle("/say", commands.Say)
@@ -27,8 +34,16 @@ func main() {
 	utils.Bot.Handle("/kick", commands.Kick)
 	utils.Bot.Handle("/ban", commands.Ban)
 	utils.Bot.Handle("/unban", commands.Unban)
+	utils.Bot.Handle("/fed", commands.Fed)
+	utils.Bot.Handle("/fban", commands.Fban)
+	utils.Bot.Handle("/funban", commands.Funban)
+	utils.Bot.Handle("/fmute", commands.Fmute)
 	utils.Bot.Handle("/mute", commands.Mute)
 	utils.Bot.Handle("/unmute", commands.Unmute)
+	utils.Bot.Handle("/mutelist", commands.Mutelist)
+	utils.Bot.Handle("/banlist", commands.Banlist)
+	utils.Bot.Handle("/perms", commands.Perms)
+	utils.Bot.Handle("/raid", commands.Raid)
 	utils.Bot.Handle("/revive", commands.Revive)
 	utils.Bot.Handle("/resurrect", commands.Revive)
 	utils.Bot.Handle("/me", commands.Me)
@@ -37,7 +52,18 @@ func main() {
 	utils.Bot.Handle("/slap", commands.Slap)
 	utils.Bot.Handle("/releases", commands.Releases)
 	utils.Bot.Handle("/warn", commands.Warn)
+	utils.Bot.Handle("/unwarn", commands.Unwarn)
+	utils.Bot.Handle("/warns", commands.Warns)
 	utils.Bot.Handle("/mywarns", commands.Mywarns)
+	utils.Bot.Handle("/report", commands.Report)
+	utils.Bot.Handle("/appeal", commands.Appeal)
+	utils.Bot.Handle("/votemute", commands.Votemute)
+	utils.Bot.Handle("/votekick", commands.Votekick)
+	utils.Bot.Handle("/trust", commands.Trust)
+	utils.Bot.Handle("/purge", commands.Purge)
+	utils.Bot.Handle("/modlog", commands.Modlog)
+	utils.Bot.Handle("/note", commands.Note)
+	utils.Bot.Handle("/notes", commands.Notes)
 	utils.Bot.Handle("/pidorules", commands.Pidorules)
 	utils.Bot.Handle("/pidoreg", commands.Pidoreg)
 	utils.Bot.Handle("/pidorme", commands.Pidorme)
@@ -50,17 +76,51 @@ func main() {
 	utils.Bot.Handle("/suicide", commands.Blessing)
 	utils.Bot.Handle("/kill", commands.Kill)
 	utils.Bot.Handle("/duelstats", commands.Duelstats)
+	utils.Bot.Handle("/trigger", commands.Trigger)
+	utils.Bot.Handle("/blacklist", commands.Blacklist)
 
 	//Inline
 	utils.Bot.Handle(tb.OnQuery, services.OnInline)
+	utils.Bot.Handle(tb.OnChosenInlineResult, services.OnInlineResult)
+
+	//Getall paginator
+	utils.Bot.Handle(&commands.GetallCategoriesButton, commands.GetallCategories)
+	utils.Bot.Handle(&commands.GetallCategoryButton, commands.GetallCategory)
+	utils.Bot.Handle(&commands.GetallGetButton, commands.GetallGet)
+
+	//Report buttons
+	utils.Bot.Handle(&commands.ReportButton, commands.ReportAction)
+
+	//Appeal buttons
+	utils.Bot.Handle(&commands.AppealButton, commands.AppealAction)
+
+	//Vote buttons
+	utils.Bot.Handle(&commands.VoteButton, commands.VoteAction)
+
+	//Undo button
+	utils.Bot.Handle(&utils.UndoButton, commands.Undo)
+
+	//User choice buttons
+	utils.Bot.Handle(&utils.UserChoiceButton, commands.UserChoice)
 
 	//Russian Roulette game
 	utils.Bot.Handle("/russianroulette", roulette.Request)
 	utils.Bot.Handle(&roulette.AcceptButton, roulette.Accept)
 	utils.Bot.Handle(&roulette.DenyButton, roulette.Deny)
 
-	//Gather user data on text
+	//Gather user data, check blacklist and flood on text
 	utils.Bot.Handle(tb.OnText, services.OnText)
+	utils.Bot.Handle(tb.OnSticker, services.OnSticker)
+	utils.Bot.Handle(tb.OnEdited, services.OnEdited)
+
+	//Remember albums for /set, check blacklist and flood on media
+	utils.Bot.Handle(tb.OnPhoto, services.OnMedia)
+	utils.Bot.Handle(tb.OnVideo, services.OnMedia)
+	utils.Bot.Handle(tb.OnAnimation, services.OnMedia)
+	utils.Bot.Handle(tb.OnDocument, services.OnMedia)
+	utils.Bot.Handle(tb.OnAudio, services.OnMedia)
+	utils.Bot.Handle(tb.OnVoice, services.OnMedia)
+	utils.Bot.Handle(tb.OnVideoNote, services.OnMedia)
 
 	//Repost channel post to chat
 	utils.Bot.Handle(tb.OnChannelPost, services.OnPost)
@@ -76,6 +136,9 @@ func main() {
 	//Services
 	go services.ZavtraStreamCheckService()
 	go welcome.JoinMessageUpdateService()
+	go services.GetExpiryService()
+	go services.RestrictionExpiryService()
+	go utils.RaidResume()
 
 	utils.Bot.Start()
 }